@@ -0,0 +1,133 @@
+package pocketbook_cloud_client
+
+// ReadStatus is the reading state of the book.
+// Values unknown to the package are kept as received, see Known.
+type ReadStatus string
+
+const (
+	ReadStatusUnread  ReadStatus = "unread"
+	ReadStatusReading ReadStatus = "reading"
+	ReadStatusRead    ReadStatus = "read"
+)
+
+// Known reports whether the value is one of the ReadStatus constants.
+func (s ReadStatus) Known() bool {
+	switch s {
+	case ReadStatusUnread, ReadStatusReading, ReadStatusRead:
+		return true
+	}
+
+	return false
+}
+
+func (s ReadStatus) String() string { return string(s) }
+
+func (s ReadStatus) MarshalText() ([]byte, error) { return []byte(s), nil }
+
+func (s *ReadStatus) UnmarshalText(text []byte) error {
+	*s = ReadStatus(text)
+
+	return nil
+}
+
+// Format is the file format of the book.
+// Values unknown to the package are kept as received, see Known.
+type Format string
+
+const (
+	FormatEPUB Format = "epub"
+	FormatPDF  Format = "pdf"
+	FormatFB2  Format = "fb2"
+	FormatMOBI Format = "mobi"
+	FormatDJVU Format = "djvu"
+	FormatCBZ  Format = "cbz"
+	FormatTXT  Format = "txt"
+	FormatMP3  Format = "mp3"
+	FormatM4B  Format = "m4b"
+)
+
+// Known reports whether the value is one of the Format constants.
+func (f Format) Known() bool {
+	switch f {
+	case FormatEPUB, FormatPDF, FormatFB2, FormatMOBI, FormatDJVU, FormatCBZ, FormatTXT, FormatMP3, FormatM4B:
+		return true
+	}
+
+	return false
+}
+
+func (f Format) String() string { return string(f) }
+
+func (f Format) MarshalText() ([]byte, error) { return []byte(f), nil }
+
+func (f *Format) UnmarshalText(text []byte) error {
+	*f = Format(text)
+
+	return nil
+}
+
+// Action is the last change of the book in the library.
+// Values unknown to the package are kept as received, see Known.
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// Known reports whether the value is one of the Action constants.
+func (a Action) Known() bool {
+	switch a {
+	case ActionCreate, ActionUpdate, ActionDelete:
+		return true
+	}
+
+	return false
+}
+
+func (a Action) String() string { return string(a) }
+
+func (a Action) MarshalText() ([]byte, error) { return []byte(a), nil }
+
+func (a *Action) UnmarshalText(text []byte) error {
+	*a = Action(text)
+
+	return nil
+}
+
+// MimeType is the media type of the book file.
+// Values unknown to the package are kept as received, see Known.
+type MimeType string
+
+const (
+	MimeTypeEPUB MimeType = "application/epub+zip"
+	MimeTypePDF  MimeType = "application/pdf"
+	MimeTypeFB2  MimeType = "application/x-fictionbook+xml"
+	MimeTypeMOBI MimeType = "application/x-mobipocket-ebook"
+	MimeTypeDJVU MimeType = "image/vnd.djvu"
+	MimeTypeCBZ  MimeType = "application/vnd.comicbook+zip"
+	MimeTypeTXT  MimeType = "text/plain"
+	MimeTypeMP3  MimeType = "audio/mpeg"
+	MimeTypeM4B  MimeType = "audio/mp4"
+)
+
+// Known reports whether the value is one of the MimeType constants.
+func (m MimeType) Known() bool {
+	switch m {
+	case MimeTypeEPUB, MimeTypePDF, MimeTypeFB2, MimeTypeMOBI, MimeTypeDJVU, MimeTypeCBZ, MimeTypeTXT, MimeTypeMP3, MimeTypeM4B:
+		return true
+	}
+
+	return false
+}
+
+func (m MimeType) String() string { return string(m) }
+
+func (m MimeType) MarshalText() ([]byte, error) { return []byte(m), nil }
+
+func (m *MimeType) UnmarshalText(text []byte) error {
+	*m = MimeType(text)
+
+	return nil
+}
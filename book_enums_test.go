@@ -0,0 +1,43 @@
+package pocketbook_cloud_client_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pbc "github.com/micronull/pocketbook-cloud-client"
+)
+
+func TestEnums_Text(t *testing.T) {
+	t.Parallel()
+
+	type enums struct {
+		ReadStatus pbc.ReadStatus `json:"read_status"`
+		Format     pbc.Format     `json:"format"`
+		Action     pbc.Action     `json:"action"`
+		MimeType   pbc.MimeType   `json:"mime_type"`
+	}
+
+	var got enums
+
+	require.NoError(t, json.Unmarshal([]byte(`{"read_status":"reading","format":"epub","action":"delete","mime_type":"application/pdf"}`), &got))
+
+	assert.Equal(t, enums{pbc.ReadStatusReading, pbc.FormatEPUB, pbc.ActionDelete, pbc.MimeTypePDF}, got)
+	assert.True(t, isAllTrue(got.ReadStatus.Known(), got.Format.Known(), got.Action.Known(), got.MimeType.Known()))
+
+	data := `{"read_status":"abandoned","format":"azw3","action":"restore","mime_type":"application/x-something"}`
+
+	require.NoError(t, json.Unmarshal([]byte(data), &got))
+
+	assert.False(t, got.ReadStatus.Known())
+	assert.False(t, got.Format.Known())
+	assert.False(t, got.Action.Known())
+	assert.False(t, got.MimeType.Known())
+	assert.Equal(t, "abandoned", got.ReadStatus.String())
+	assert.Equal(t, "azw3", got.Format.String())
+
+	// unknown values survive the round trip
+	assert.JSONEq(t, data, string(must(json.Marshal(got))))
+}
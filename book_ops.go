@@ -0,0 +1,155 @@
+package pocketbook_cloud_client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+var (
+	errNoBookKey  = errors.New("book has neither path nor id")
+	errNoFastHash = errors.New("book has no fast hash")
+	errNoBookPath = errors.New("book has no path")
+	errNoBookName = errors.New("book has neither name nor path")
+	errBadName    = errors.New("invalid book name")
+)
+
+// DeleteBook deletes the book from the library.
+// The book is addressed by Book.Path, or by Book.ID when the path is empty.
+// The request is guarded by Book.FastHash: when the file has been changed since it was listed,
+// the error matching ErrConflict is returned and nothing is deleted.
+func (c Client) DeleteBook(ctx context.Context, token string, book Book) error {
+	req, err := c.bookRequest(ctx, http.MethodDelete, token, book, nil)
+	if err != nil {
+		return fmt.Errorf("delete book %s: %w", bookKey(book), err)
+	}
+
+	if _, err = c.req(OperationDeleteBook, req); err != nil {
+		return fmt.Errorf("delete book %s: %w", bookKey(book), err)
+	}
+
+	return nil
+}
+
+// RenameBook renames the book within its folder and returns the updated book.
+// The folder is taken from Book.Path, so the book known only by Book.ID can not be renamed.
+// The name must not be empty, "." or ".." and must not contain "/", use MoveBook to change the folder.
+// It is guarded by Book.FastHash like DeleteBook.
+func (c Client) RenameBook(ctx context.Context, token string, book Book, name string) (Book, error) {
+	if book.Path == "" {
+		return Book{}, fmt.Errorf("rename book %s: %w", bookKey(book), errNoBookPath)
+	}
+
+	if name == "" || name == "." || name == ".." || strings.Contains(name, "/") {
+		return Book{}, fmt.Errorf("rename book %s: %w %q", bookKey(book), errBadName, name)
+	}
+
+	updated, err := c.moveBook(ctx, OperationRenameBook, token, book, path.Join(path.Dir(libraryPath(book.Path)), name))
+	if err != nil {
+		return Book{}, fmt.Errorf("rename book %s: %w", bookKey(book), err)
+	}
+
+	return updated, nil
+}
+
+// MoveBook moves the book to the folder keeping its name and returns the updated book.
+// The name is taken from Book.Name or the base of Book.Path.
+// It is guarded by Book.FastHash like DeleteBook.
+func (c Client) MoveBook(ctx context.Context, token string, book Book, folder string) (Book, error) {
+	name := book.Name
+	if name == "" && book.Path != "" {
+		name = path.Base(book.Path)
+	}
+
+	if name == "" {
+		return Book{}, fmt.Errorf("move book %s: %w", bookKey(book), errNoBookName)
+	}
+
+	updated, err := c.moveBook(ctx, OperationMoveBook, token, book, libraryPath(path.Join(folder, name)))
+	if err != nil {
+		return Book{}, fmt.Errorf("move book %s: %w", bookKey(book), err)
+	}
+
+	return updated, nil
+}
+
+func (c Client) moveBook(ctx context.Context, op, token string, book Book, to string) (Book, error) {
+	payload, err := json.Marshal(struct {
+		Path string `json:"path"`
+	}{Path: to})
+	if err != nil {
+		return Book{}, fmt.Errorf("marshal request body: %w", err)
+	}
+
+	req, err := c.bookRequest(ctx, http.MethodPatch, token, book, payload)
+	if err != nil {
+		return Book{}, err
+	}
+
+	body, err := c.req(op, req)
+	if err != nil {
+		return Book{}, err
+	}
+
+	var item bookItem
+
+	if err = c.decode(op, body, &item); err != nil {
+		return Book{}, err
+	}
+
+	return mappingBook(item), nil
+}
+
+// bookRequest builds the request to the file of the book guarded by its fast hash.
+func (c Client) bookRequest(ctx context.Context, method, token string, book Book, payload []byte) (*http.Request, error) {
+	var u *url.URL
+
+	switch {
+	case book.Path != "":
+		u = c.url(files).JoinPath(libraryPath(book.Path))
+	case book.ID != "":
+		u = c.url(books).JoinPath(book.ID)
+	default:
+		return nil, errNoBookKey
+	}
+
+	if book.FastHash == "" {
+		return nil, errNoFastHash
+	}
+
+	u.RawQuery = url.Values{"fast_hash": []string{book.FastHash}}.Encode()
+
+	req := &http.Request{
+		Method: method,
+		URL:    u,
+		Body:   http.NoBody,
+		Header: http.Header{"Authorization": []string{string(TokenTypeBearer) + " " + token}},
+	}
+
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+		req.Body = io.NopCloser(bytes.NewReader(payload))
+		req.ContentLength = int64(len(payload))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(payload)), nil
+		}
+	}
+
+	return req.WithContext(ctx), nil
+}
+
+// bookKey is the book in error messages.
+func bookKey(book Book) string {
+	if book.Path != "" {
+		return book.Path
+	}
+
+	return "id=" + book.ID
+}
@@ -0,0 +1,171 @@
+package pocketbook_cloud_client_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pbc "github.com/micronull/pocketbook-cloud-client"
+)
+
+func TestClient_DeleteBook(t *testing.T) {
+	t.Parallel()
+
+	fs, client := newFileServer(t)
+	fs.put("/voina-i-mir.epub", epubFile(), string(pbc.MimeTypeEPUB), "")
+
+	require.NoError(t, client.DeleteBook(context.Background(), "some.token", fs.book(t, "/voina-i-mir.epub")))
+	assert.Nil(t, fs.body("/voina-i-mir.epub"))
+}
+
+func TestClient_DeleteBook_ByID(t *testing.T) {
+	t.Parallel()
+
+	fs, client := newFileServer(t)
+	fs.put("/voina-i-mir.epub", epubFile(), string(pbc.MimeTypeEPUB), "")
+
+	book := fs.book(t, "/voina-i-mir.epub")
+	book.Path = ""
+
+	require.NoError(t, client.DeleteBook(context.Background(), "some.token", book))
+	assert.Nil(t, fs.body("/voina-i-mir.epub"))
+}
+
+func TestClient_DeleteBook_Changed(t *testing.T) {
+	t.Parallel()
+
+	fs, client := newFileServer(t)
+	fs.put("/voina-i-mir.epub", epubFile(), string(pbc.MimeTypeEPUB), "")
+
+	book := fs.book(t, "/voina-i-mir.epub")
+
+	changed := append(epubFile(), "new revision"...)
+	fs.put("/voina-i-mir.epub", changed, string(pbc.MimeTypeEPUB), "")
+
+	err := client.DeleteBook(context.Background(), "some.token", book)
+	require.ErrorIs(t, err, pbc.ErrConflict)
+
+	assert.Equal(t, changed, fs.body("/voina-i-mir.epub"))
+}
+
+func TestClient_DeleteBook_NoFastHash(t *testing.T) {
+	t.Parallel()
+
+	_, client := newFileServer(t)
+
+	err := client.DeleteBook(context.Background(), "some.token", pbc.Book{Path: "/voina-i-mir.epub"})
+	require.Error(t, err)
+}
+
+func TestClient_RenameBook(t *testing.T) {
+	t.Parallel()
+
+	fs, client := newFileServer(t)
+	fs.put("/classic/voina-i-mir.epub", epubFile(), string(pbc.MimeTypeEPUB), "")
+
+	book, err := client.RenameBook(context.Background(), "some.token", fs.book(t, "/classic/voina-i-mir.epub"), "war-and-peace.epub")
+	require.NoError(t, err)
+
+	assert.Equal(t, "/classic/war-and-peace.epub", book.Path)
+	assert.Equal(t, "war-and-peace.epub", book.Name)
+	assert.Equal(t, epubFile(), fs.body("/classic/war-and-peace.epub"))
+	assert.Nil(t, fs.body("/classic/voina-i-mir.epub"))
+}
+
+func TestClient_RenameBook_ByID(t *testing.T) {
+	t.Parallel()
+
+	fs, client := newFileServer(t)
+	fs.put("/classic/voina-i-mir.epub", epubFile(), string(pbc.MimeTypeEPUB), "")
+
+	book := fs.book(t, "/classic/voina-i-mir.epub")
+	book.Path = ""
+
+	// the folder of the book is unknown, it must not be moved to the root
+	_, err := client.RenameBook(context.Background(), "some.token", book, "war-and-peace.epub")
+	require.Error(t, err)
+
+	assert.Equal(t, epubFile(), fs.body("/classic/voina-i-mir.epub"))
+	assert.Nil(t, fs.body("/war-and-peace.epub"))
+}
+
+func TestClient_RenameBook_BadName(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"", ".", "..", "../war-and-peace.epub", "tolstoy/war-and-peace.epub"} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			fs, client := newFileServer(t)
+			fs.put("/classic/voina-i-mir.epub", epubFile(), string(pbc.MimeTypeEPUB), "")
+
+			_, err := client.RenameBook(context.Background(), "some.token", fs.book(t, "/classic/voina-i-mir.epub"), name)
+			require.Error(t, err)
+
+			assert.Equal(t, epubFile(), fs.body("/classic/voina-i-mir.epub"))
+		})
+	}
+}
+
+func TestClient_RenameBook_Taken(t *testing.T) {
+	t.Parallel()
+
+	fs, client := newFileServer(t)
+	fs.put("/voina-i-mir.epub", epubFile(), string(pbc.MimeTypeEPUB), "")
+	fs.put("/war-and-peace.epub", []byte("%PDF-1.7"), string(pbc.MimeTypePDF), "")
+
+	_, err := client.RenameBook(context.Background(), "some.token", fs.book(t, "/voina-i-mir.epub"), "war-and-peace.epub")
+	require.ErrorIs(t, err, pbc.ErrConflict)
+
+	assert.Equal(t, epubFile(), fs.body("/voina-i-mir.epub"))
+}
+
+func TestClient_MoveBook(t *testing.T) {
+	t.Parallel()
+
+	fs, client := newFileServer(t)
+	fs.put("/voina-i-mir.epub", epubFile(), string(pbc.MimeTypeEPUB), "")
+
+	book, err := client.MoveBook(context.Background(), "some.token", fs.book(t, "/voina-i-mir.epub"), "classic/tolstoy")
+	require.NoError(t, err)
+
+	assert.Equal(t, "/classic/tolstoy/voina-i-mir.epub", book.Path)
+	assert.Equal(t, epubFile(), fs.body("/classic/tolstoy/voina-i-mir.epub"))
+	assert.Nil(t, fs.body("/voina-i-mir.epub"))
+}
+
+func TestClient_MoveBook_ByID(t *testing.T) {
+	t.Parallel()
+
+	fs, client := newFileServer(t)
+	fs.put("/voina-i-mir.epub", epubFile(), string(pbc.MimeTypeEPUB), "")
+
+	book := fs.book(t, "/voina-i-mir.epub")
+	book.Path = ""
+
+	moved, err := client.MoveBook(context.Background(), "some.token", book, "classic")
+	require.NoError(t, err)
+
+	assert.Equal(t, "/classic/voina-i-mir.epub", moved.Path)
+	assert.Equal(t, epubFile(), fs.body("/classic/voina-i-mir.epub"))
+
+	// the book known only by id has no name to keep, it must not be renamed to the folder
+	_, err = client.MoveBook(context.Background(), "some.token", pbc.Book{ID: moved.ID, FastHash: moved.FastHash}, "tolstoy")
+	require.Error(t, err)
+
+	assert.Equal(t, epubFile(), fs.body("/classic/voina-i-mir.epub"))
+	assert.Nil(t, fs.body("/tolstoy"))
+}
+
+func TestClient_MoveBook_NotFound(t *testing.T) {
+	t.Parallel()
+
+	_, client := newFileServer(t)
+
+	book := pbc.Book{Path: "/voina-i-mir.epub", Name: "voina-i-mir.epub", FastHash: "5c624ec0db399a8f1b99eddabf1e22c1"}
+
+	_, err := client.MoveBook(context.Background(), "some.token", book, "classic")
+	require.ErrorIs(t, err, pbc.ErrNotFound)
+}
@@ -2,7 +2,6 @@ package pocketbook_cloud_client
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -10,246 +9,178 @@ import (
 )
 
 type Books struct {
-	Total int
-	Books []Book
+	Total int    `json:"total"`
+	Books []Book `json:"books"`
 }
 
 type Book struct {
-	ID           string
-	Path         string
-	Title        string
-	MimeType     string
-	CreatedAt    time.Time
-	Purchased    bool
-	Bytes        int
-	ClientMtime  time.Time
-	FastHash     string
-	Favorite     bool
-	ReadStatus   string
-	Link         string
-	HasLinks     bool
-	Format       string
-	Md5Hash      string
-	Mtime        time.Time
-	Name         string
-	ReadPercent  int
-	Percent      string
-	IsDrm        bool
-	IsLcp        bool
-	IsAudioBook  bool
-	MetaData     BookMetaData
-	Position     BookPosition
-	ReadPosition BookReadPosition
-	Action       string
-	ActionDate   time.Time
+	ID           string           `json:"id"`
+	Path         string           `json:"path"`
+	Title        string           `json:"title"`
+	MimeType     MimeType         `json:"mime_type"`
+	CreatedAt    time.Time        `json:"created_at"`
+	Purchased    bool             `json:"purchased"`
+	ResourceID   string           `json:"resource_id"`
+	Bytes        int              `json:"bytes"`
+	ClientMtime  time.Time        `json:"client_mtime"`
+	Collections  []string         `json:"collections"`
+	FastHash     string           `json:"fast_hash"`
+	Favorite     bool             `json:"favorite"`
+	ReadStatus   ReadStatus       `json:"read_status"`
+	Link         string           `json:"link"`
+	HasLinks     bool             `json:"has_links"`
+	Format       Format           `json:"format"`
+	Md5Hash      string           `json:"md5_hash"`
+	Mtime        time.Time        `json:"mtime"`
+	Name         string           `json:"name"`
+	ReadPercent  int              `json:"read_percent"`
+	IsDrm        bool             `json:"is_drm"`
+	IsLcp        bool             `json:"is_lcp"`
+	IsAudioBook  bool             `json:"is_audio_book"`
+	MetaData     BookMetaData     `json:"metadata"`
+	Position     BookPosition     `json:"position"`
+	ReadPosition BookReadPosition `json:"read_position"`
+	Action       Action           `json:"action"`
+	ActionDate   time.Time        `json:"action_date"`
 }
 
 type BookMetaData struct {
-	Title       string
-	Authors     string
-	Cover       []BookCover
-	Lang        string
-	Publisher   string
-	Updated     time.Time
-	Year        int
-	Isbn        string
-	BookId      []string
-	FixedLayout bool
+	Title       string              `json:"title"`
+	TrackNumber Optional[int]       `json:"track_number"`
+	Authors     string              `json:"authors"`
+	Cover       []BookCover         `json:"cover"`
+	Genres      []string            `json:"genres"`
+	Lang        string              `json:"lang"`
+	Publisher   Optional[string]    `json:"publisher"`
+	Size        Optional[int64]     `json:"size"`
+	Duration    Optional[int]       `json:"duration"` // seconds of the audiobook
+	Updated     Optional[time.Time] `json:"updated"`
+	Year        Optional[int]       `json:"year"`
+	Isbn        Optional[string]    `json:"isbn"`
+	Series      Optional[string]    `json:"series"`
+	Annotation  Optional[string]    `json:"annotation"`
+	BookId      []string            `json:"book_id"`
+	FixedLayout bool                `json:"fixed_layout"`
+	SeriesOrd   Optional[int]       `json:"series_ord"`
+}
+
+func (m BookMetaData) MarshalJSON() ([]byte, error) {
+	type metaData BookMetaData
+
+	return marshalPresent(metaData(m))
 }
 
 type BookCover struct {
-	Width  int
-	Height int
-	Path   string
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Path   string `json:"path"`
 }
 
 type BookPosition struct {
-	Pointer    string
-	PointerPb  string
-	Percent    int
-	Page       string
-	PagesTotal int
-	Updated    time.Time
-	Offs       int
+	Pointer    Optional[string]    `json:"pointer"`
+	PointerPb  Optional[string]    `json:"pointer_pb"`
+	Percent    Optional[int]       `json:"percent"`
+	Page       Optional[string]    `json:"page"`
+	PagesTotal Optional[int]       `json:"pages_total"`
+	Updated    Optional[time.Time] `json:"updated"`
+	Offs       Optional[int]       `json:"offs"`
 }
 
-type BookReadPosition struct {
-	Pointer    string
-	PointerPb  string
-	Percent    int
-	Page       string
-	PagesTotal int
-	Updated    time.Time
-	Offs       int
+func (p BookPosition) MarshalJSON() ([]byte, error) {
+	type position BookPosition
+
+	return marshalPresent(position(p))
 }
 
-func (c Client) Books(ctx context.Context, token string, limit, offset int) (Books, error) {
-	u := c.url(books)
-	q := u.Query()
+// IsSet reports whether the position is stored, as opposed to the book never opened.
+func (p BookPosition) IsSet() bool {
+	return p.Pointer.Valid() || p.PointerPb.Valid() || p.Page.Valid() || p.Updated.Valid()
+}
 
-	q.Set("limit", strconv.Itoa(limit))
+type BookReadPosition struct {
+	Pointer    Optional[string]    `json:"pointer"`
+	PointerPb  Optional[string]    `json:"pointer_pb"`
+	Percent    Optional[int]       `json:"percent"`
+	Page       Optional[string]    `json:"page"`
+	PagesTotal Optional[int]       `json:"pages_total"`
+	Updated    Optional[time.Time] `json:"updated"`
+	Offs       Optional[int]       `json:"offs"`
+}
 
-	if offset > 0 {
-		q.Set("offset", strconv.Itoa(offset))
-	}
+func (p BookReadPosition) MarshalJSON() ([]byte, error) {
+	type position BookReadPosition
 
-	u.RawQuery = q.Encode()
+	return marshalPresent(position(p))
+}
 
-	req := &http.Request{
-		Method: http.MethodGet,
-		URL:    u,
-		Body:   http.NoBody,
-		Header: http.Header{"Authorization": []string{string(TokenTypeBearer) + " " + token}},
-	}
+// IsSet reports whether the read position is stored, as opposed to the book never read.
+func (p BookReadPosition) IsSet() bool {
+	return p.Pointer.Valid() || p.PointerPb.Valid() || p.Page.Valid() || p.Updated.Valid()
+}
+
+// HasPosition reports whether the book has the stored position.
+func (b Book) HasPosition() bool {
+	return b.Position.IsSet()
+}
 
-	req = req.WithContext(ctx)
+// HasReadPosition reports whether the book has the stored read position.
+func (b Book) HasReadPosition() bool {
+	return b.ReadPosition.IsSet()
+}
 
-	body, err := c.req(req)
+func (c Client) Books(ctx context.Context, token string, limit, offset int) (Books, error) {
+	body, err := c.req(OperationBooks, c.booksRequest(ctx, token, limit, offset))
 	if err != nil {
 		return Books{}, fmt.Errorf("get books: %w", err)
 	}
 
 	var data struct {
-		Total int `json:"total"`
-		Items []struct {
-			ID          string    `json:"id"`
-			Path        string    `json:"path"`
-			Title       string    `json:"title"`
-			MimeType    string    `json:"mime_type"`
-			CreatedAt   time.Time `json:"created_at"`
-			Purchased   bool      `json:"purchased"`
-			Bytes       int       `json:"bytes"`
-			ClientMtime time.Time `json:"client_mtime"`
-			FastHash    string    `json:"fast_hash"`
-			Favorite    bool      `json:"favorite"`
-			ReadStatus  string    `json:"read_status"`
-			Link        string    `json:"link"`
-			HasLinks    bool      `json:"hasLinks"`
-			Format      string    `json:"format"`
-			Md5Hash     string    `json:"md5_hash"`
-			Mtime       time.Time `json:"mtime"`
-			Name        string    `json:"name"`
-			ReadPercent int       `json:"read_percent"`
-			Percent     string    `json:"percent"`
-			IsDrm       bool      `json:"isDrm"`
-			IsLcp       bool      `json:"isLcp"`
-			IsAudioBook bool      `json:"isAudioBook"`
-			Metadata    struct {
-				Title   string `json:"title"`
-				Authors string `json:"authors"`
-				Cover   []struct {
-					Width  int    `json:"width"`
-					Height int    `json:"height"`
-					Path   string `json:"path"`
-				} `json:"cover"`
-				Lang        string    `json:"lang"`
-				Publisher   string    `json:"publisher"`
-				Updated     time.Time `json:"updated"`
-				Year        int       `json:"year"`
-				Isbn        string    `json:"isbn"`
-				BookId      []string  `json:"book_id"`
-				FixedLayout bool      `json:"fixed_layout"`
-			} `json:"metadata"`
-			Position struct {
-				Pointer    string    `json:"pointer"`
-				PointerPb  string    `json:"pointer_pb"`
-				Percent    int       `json:"percent"`
-				Page       string    `json:"page"`
-				PagesTotal int       `json:"pages_total"`
-				Updated    time.Time `json:"updated"`
-				Offs       int       `json:"offs"`
-			} `json:"position"`
-			ReadPosition struct {
-				Pointer    string    `json:"pointer"`
-				PointerPb  string    `json:"pointer_pb"`
-				Percent    int       `json:"percent"`
-				Page       string    `json:"page"`
-				PagesTotal int       `json:"pages_total"`
-				Updated    time.Time `json:"updated"`
-				Offs       int       `json:"offs"`
-			} `json:"read_position"`
-			Action     string    `json:"action"`
-			ActionDate time.Time `json:"action_date"`
-		} `json:"items"`
+		Total lenientInt `json:"total"`
+		Items []bookItem `json:"items"`
 	}
 
-	if err = json.Unmarshal(body, &data); err != nil {
-		return Books{}, fmt.Errorf("unmarshal response body: %w", err)
+	if err = c.decode(OperationBooks, body, &data); err != nil {
+		return Books{}, fmt.Errorf("get books: %w", err)
 	}
 
 	books := Books{
-		Total: data.Total,
+		Total: int(data.Total),
 		Books: make([]Book, len(data.Items)),
 	}
 
 	for i := 0; i < len(data.Items); i++ {
-		item := data.Items[i]
-
-		books.Books[i] = Book{
-			ID:          item.ID,
-			Path:        item.Path,
-			Title:       item.Title,
-			MimeType:    item.MimeType,
-			CreatedAt:   item.CreatedAt,
-			Purchased:   item.Purchased,
-			Bytes:       item.Bytes,
-			ClientMtime: item.ClientMtime,
-			FastHash:    item.FastHash,
-			Favorite:    item.Favorite,
-			ReadStatus:  item.ReadStatus,
-			Link:        item.Link,
-			HasLinks:    item.HasLinks,
-			Format:      item.Format,
-			Md5Hash:     item.Md5Hash,
-			Mtime:       item.Mtime,
-			Name:        item.Name,
-			ReadPercent: item.ReadPercent,
-			Percent:     item.Percent,
-			IsDrm:       item.IsDrm,
-			IsLcp:       item.IsLcp,
-			IsAudioBook: item.IsAudioBook,
-			MetaData: BookMetaData{
-				Title:       item.Metadata.Title,
-				Authors:     item.Metadata.Authors,
-				Cover:       mappingBookCovers(item.Metadata.Cover),
-				Lang:        item.Metadata.Lang,
-				Publisher:   item.Metadata.Publisher,
-				Updated:     item.Metadata.Updated,
-				Year:        item.Metadata.Year,
-				Isbn:        item.Metadata.Isbn,
-				BookId:      item.Metadata.BookId,
-				FixedLayout: item.Metadata.FixedLayout,
-			},
-			Position: BookPosition{
-				Pointer:    item.Position.Pointer,
-				PointerPb:  item.Position.PointerPb,
-				Percent:    item.Position.Percent,
-				Page:       item.Position.Page,
-				PagesTotal: item.Position.PagesTotal,
-				Updated:    item.Position.Updated,
-				Offs:       item.Position.Offs,
-			},
-			ReadPosition: BookReadPosition{
-				Pointer:    item.ReadPosition.Pointer,
-				PointerPb:  item.ReadPosition.PointerPb,
-				Percent:    item.ReadPosition.Percent,
-				Page:       item.ReadPosition.Page,
-				PagesTotal: item.ReadPosition.PagesTotal,
-				Updated:    item.ReadPosition.Updated,
-				Offs:       item.ReadPosition.Offs,
-			},
-			Action:     item.Action,
-			ActionDate: item.ActionDate,
-		}
+		books.Books[i] = mappingBook(data.Items[i])
 	}
 
 	return books, nil
 }
 
+func (c Client) booksRequest(ctx context.Context, token string, limit, offset int) *http.Request {
+	u := c.url(books)
+	q := u.Query()
+
+	q.Set("limit", strconv.Itoa(limit))
+
+	if offset > 0 {
+		q.Set("offset", strconv.Itoa(offset))
+	}
+
+	u.RawQuery = q.Encode()
+
+	req := &http.Request{
+		Method: http.MethodGet,
+		URL:    u,
+		Body:   http.NoBody,
+		Header: http.Header{"Authorization": []string{string(TokenTypeBearer) + " " + token}},
+	}
+
+	return req.WithContext(ctx)
+}
+
 func mappingBookCovers(covers []struct {
-	Width  int    `json:"width"`
-	Height int    `json:"height"`
-	Path   string `json:"path"`
+	Width  lenientInt `json:"width"`
+	Height lenientInt `json:"height"`
+	Path   string     `json:"path"`
 }) []BookCover {
 	if len(covers) == 0 {
 		return nil
@@ -259,11 +190,163 @@ func mappingBookCovers(covers []struct {
 
 	for i := 0; i < len(covers); i++ {
 		cs[i] = BookCover{
-			Width:  covers[i].Width,
-			Height: covers[i].Height,
+			Width:  int(covers[i].Width),
+			Height: int(covers[i].Height),
 			Path:   covers[i].Path,
 		}
 	}
 
 	return cs
 }
+
+// readPercent returns read_percent falling back to the legacy percent string.
+func readPercent(readPercent int, percent string) int {
+	if readPercent != 0 || percent == "" {
+		return readPercent
+	}
+
+	p, err := strconv.Atoi(percent)
+	if err != nil {
+		return 0
+	}
+
+	return p
+}
+
+// bookItem is the book in the API response.
+type bookItem struct {
+	ID          lenientString `json:"id"`
+	Path        string        `json:"path"`
+	Title       string        `json:"title"`
+	MimeType    MimeType      `json:"mime_type"`
+	CreatedAt   time.Time     `json:"created_at"`
+	Purchased   bool          `json:"purchased"`
+	ResourceID  string        `json:"resource_id"`
+	Bytes       lenientInt    `json:"bytes"`
+	ClientMtime time.Time     `json:"client_mtime"`
+	Collections []string      `json:"collections"`
+	FastHash    string        `json:"fast_hash"`
+	Favorite    bool          `json:"favorite"`
+	ReadStatus  ReadStatus    `json:"read_status"`
+	Link        string        `json:"link"`
+	HasLinks    bool          `json:"hasLinks"`
+	Format      Format        `json:"format"`
+	Md5Hash     string        `json:"md5_hash"`
+	Mtime       time.Time     `json:"mtime"`
+	Name        string        `json:"name"`
+	ReadPercent lenientInt    `json:"read_percent"`
+	Percent     lenientString `json:"percent"`
+	IsDrm       bool          `json:"isDrm"`
+	IsLcp       bool          `json:"isLcp"`
+	IsAudioBook bool          `json:"isAudioBook"`
+	Metadata    struct {
+		Title       string               `json:"title"`
+		TrackNumber Optional[lenientInt] `json:"track_number"`
+		Authors     string               `json:"authors"`
+		Cover       []struct {
+			Width  lenientInt `json:"width"`
+			Height lenientInt `json:"height"`
+			Path   string     `json:"path"`
+		} `json:"cover"`
+		Genres      []string             `json:"genres"`
+		Lang        string               `json:"lang"`
+		Publisher   Optional[string]     `json:"publisher"`
+		Size        Optional[lenientInt] `json:"size"`
+		Duration    Optional[lenientInt] `json:"duration"`
+		Updated     Optional[time.Time]  `json:"updated"`
+		Year        Optional[lenientInt] `json:"year"`
+		Isbn        Optional[string]     `json:"isbn"`
+		Series      Optional[string]     `json:"series"`
+		Annotation  Optional[string]     `json:"annotation"`
+		BookId      []string             `json:"book_id"`
+		FixedLayout bool                 `json:"fixed_layout"`
+		SeriesOrd   Optional[lenientInt] `json:"series_ord"`
+	} `json:"metadata"`
+	Position struct {
+		Pointer    Optional[string]        `json:"pointer"`
+		PointerPb  Optional[string]        `json:"pointer_pb"`
+		Percent    Optional[lenientInt]    `json:"percent"`
+		Page       Optional[lenientString] `json:"page"`
+		PagesTotal Optional[lenientInt]    `json:"pages_total"`
+		Updated    Optional[time.Time]     `json:"updated"`
+		Offs       Optional[lenientInt]    `json:"offs"`
+	} `json:"position"`
+	ReadPosition struct {
+		Pointer    Optional[string]        `json:"pointer"`
+		PointerPb  Optional[string]        `json:"pointer_pb"`
+		Percent    Optional[lenientInt]    `json:"percent"`
+		Page       Optional[lenientString] `json:"page"`
+		PagesTotal Optional[lenientInt]    `json:"pages_total"`
+		Updated    Optional[time.Time]     `json:"updated"`
+		Offs       Optional[lenientInt]    `json:"offs"`
+	} `json:"read_position"`
+	Action     Action    `json:"action"`
+	ActionDate time.Time `json:"action_date"`
+}
+
+func mappingBook(item bookItem) Book {
+	return Book{
+		ID:          string(item.ID),
+		Path:        item.Path,
+		Title:       item.Title,
+		MimeType:    item.MimeType,
+		CreatedAt:   item.CreatedAt,
+		Purchased:   item.Purchased,
+		ResourceID:  item.ResourceID,
+		Bytes:       int(item.Bytes),
+		ClientMtime: item.ClientMtime,
+		Collections: item.Collections,
+		FastHash:    item.FastHash,
+		Favorite:    item.Favorite,
+		ReadStatus:  item.ReadStatus,
+		Link:        item.Link,
+		HasLinks:    item.HasLinks,
+		Format:      item.Format,
+		Md5Hash:     item.Md5Hash,
+		Mtime:       item.Mtime,
+		Name:        item.Name,
+		ReadPercent: readPercent(int(item.ReadPercent), string(item.Percent)),
+		IsDrm:       item.IsDrm,
+		IsLcp:       item.IsLcp,
+		IsAudioBook: item.IsAudioBook,
+		MetaData: BookMetaData{
+			Title:       item.Metadata.Title,
+			TrackNumber: mapOptional(item.Metadata.TrackNumber, lenientInt.int),
+			Authors:     item.Metadata.Authors,
+			Cover:       mappingBookCovers(item.Metadata.Cover),
+			Genres:      item.Metadata.Genres,
+			Lang:        item.Metadata.Lang,
+			Publisher:   item.Metadata.Publisher,
+			Size:        mapOptional(item.Metadata.Size, lenientInt.int64),
+			Duration:    mapOptional(item.Metadata.Duration, lenientInt.int),
+			Updated:     item.Metadata.Updated,
+			Year:        mapOptional(item.Metadata.Year, lenientInt.int),
+			Isbn:        item.Metadata.Isbn,
+			Series:      item.Metadata.Series,
+			Annotation:  item.Metadata.Annotation,
+			BookId:      item.Metadata.BookId,
+			FixedLayout: item.Metadata.FixedLayout,
+			SeriesOrd:   mapOptional(item.Metadata.SeriesOrd, lenientInt.int),
+		},
+		Position: BookPosition{
+			Pointer:    item.Position.Pointer,
+			PointerPb:  item.Position.PointerPb,
+			Percent:    mapOptional(item.Position.Percent, lenientInt.int),
+			Page:       mapOptional(item.Position.Page, lenientString.string),
+			PagesTotal: mapOptional(item.Position.PagesTotal, lenientInt.int),
+			Updated:    item.Position.Updated,
+			Offs:       mapOptional(item.Position.Offs, lenientInt.int),
+		},
+		ReadPosition: BookReadPosition{
+			Pointer:    item.ReadPosition.Pointer,
+			PointerPb:  item.ReadPosition.PointerPb,
+			Percent:    mapOptional(item.ReadPosition.Percent, lenientInt.int),
+			Page:       mapOptional(item.ReadPosition.Page, lenientString.string),
+			PagesTotal: mapOptional(item.ReadPosition.PagesTotal, lenientInt.int),
+			Updated:    item.ReadPosition.Updated,
+			Offs:       mapOptional(item.ReadPosition.Offs, lenientInt.int),
+		},
+		Action:     item.Action,
+		ActionDate: item.ActionDate,
+	}
+}
@@ -0,0 +1,79 @@
+package pocketbook_cloud_client
+
+import (
+	"context"
+	"iter"
+)
+
+// DefaultPageSize is the page size used when a non-positive one is passed.
+const DefaultPageSize = 100
+
+// AllBooks iterates over all books of the library, requesting them lazily page by page.
+// Iteration stops after the first error. Books are deduplicated by ID,
+// and when the library changes during iteration the pages are re-requested so that no book is skipped:
+// the shrunk library is rewound by the number of removed books, the grown one is iterated again from the start.
+// The server may return fewer books than the page size, only the empty page or the total ends the iteration.
+func (c Client) AllBooks(ctx context.Context, token string, pageSize int) iter.Seq2[Book, error] {
+	return allBooks(ctx, pageSize, func(ctx context.Context, limit, offset int) (Books, error) {
+		return c.Books(ctx, token, limit, offset)
+	})
+}
+
+type booksPager func(ctx context.Context, limit, offset int) (Books, error)
+
+func allBooks(ctx context.Context, pageSize int, fetch booksPager) iter.Seq2[Book, error] {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	return func(yield func(Book, error) bool) {
+		seen := make(map[string]struct{})
+		offset := 0
+		total := -1
+
+		for {
+			page, err := fetch(ctx, pageSize, offset)
+			if err != nil {
+				yield(Book{}, err)
+
+				return
+			}
+
+			// books were removed, those following them are shifted to the passed offsets
+			if total > page.Total && offset > 0 {
+				offset = max(offset-(total-page.Total), 0)
+				total = page.Total
+
+				continue
+			}
+
+			// books were added, possibly before the offset, the library is iterated again skipping the seen books
+			if total < page.Total && offset > 0 {
+				offset = 0
+				total = page.Total
+
+				continue
+			}
+
+			total = page.Total
+
+			for _, b := range page.Books {
+				if _, ok := seen[b.ID]; ok {
+					continue
+				}
+
+				seen[b.ID] = struct{}{}
+
+				if !yield(b, nil) {
+					return
+				}
+			}
+
+			offset += len(page.Books)
+
+			if len(page.Books) == 0 || offset >= total {
+				return
+			}
+		}
+	}
+}
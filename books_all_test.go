@@ -0,0 +1,194 @@
+package pocketbook_cloud_client_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"iter"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	pbc "github.com/micronull/pocketbook-cloud-client"
+	"github.com/micronull/pocketbook-cloud-client/mocks"
+)
+
+func TestClient_AllBooks(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+
+	gomock.InOrder(
+		httpMock.EXPECT().Do(isBooksPage(t, 2, 0)).Return(booksResponse(5, "a", "b"), nil),
+		httpMock.EXPECT().Do(isBooksPage(t, 2, 2)).Return(booksResponse(5, "c", "d"), nil),
+		httpMock.EXPECT().Do(isBooksPage(t, 2, 4)).Return(booksResponse(5, "e"), nil),
+	)
+
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, collectIDs(t, client.AllBooks(context.Background(), "some.token", 2)))
+}
+
+func TestClient_AllBooks_Break(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+
+	httpMock.EXPECT().Do(isBooksPage(t, 2, 0)).Return(booksResponse(5, "a", "b"), nil)
+
+	for b, err := range client.AllBooks(context.Background(), "some.token", 2) {
+		require.NoError(t, err)
+		assert.Equal(t, "a", b.ID)
+
+		break
+	}
+}
+
+func TestClient_AllBooks_TotalChanged(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+
+	gomock.InOrder(
+		httpMock.EXPECT().Do(isBooksPage(t, 2, 0)).Return(booksResponse(5, "a", "b"), nil),
+		// "a" is removed, the rest are shifted
+		httpMock.EXPECT().Do(isBooksPage(t, 2, 2)).Return(booksResponse(4, "d", "e"), nil),
+		httpMock.EXPECT().Do(isBooksPage(t, 2, 1)).Return(booksResponse(4, "c", "d"), nil),
+		// "x" is added to the start, the rest are shifted
+		httpMock.EXPECT().Do(isBooksPage(t, 2, 3)).Return(booksResponse(5, "d", "e"), nil),
+		httpMock.EXPECT().Do(isBooksPage(t, 2, 0)).Return(booksResponse(5, "x", "b"), nil),
+		httpMock.EXPECT().Do(isBooksPage(t, 2, 2)).Return(booksResponse(5, "c", "d"), nil),
+		httpMock.EXPECT().Do(isBooksPage(t, 2, 4)).Return(booksResponse(5, "e"), nil),
+	)
+
+	assert.Equal(t, []string{"a", "b", "c", "d", "x", "e"}, collectIDs(t, client.AllBooks(context.Background(), "some.token", 2)))
+}
+
+func TestClient_AllBooks_Inserted(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+
+	gomock.InOrder(
+		httpMock.EXPECT().Do(isBooksPage(t, 2, 0)).Return(booksResponse(4, "a", "b"), nil),
+		// "x" is inserted before the offset, the rest are shifted
+		httpMock.EXPECT().Do(isBooksPage(t, 2, 2)).Return(booksResponse(5, "b", "c"), nil),
+		httpMock.EXPECT().Do(isBooksPage(t, 2, 0)).Return(booksResponse(5, "a", "x"), nil),
+		httpMock.EXPECT().Do(isBooksPage(t, 2, 2)).Return(booksResponse(5, "b", "c"), nil),
+		httpMock.EXPECT().Do(isBooksPage(t, 2, 4)).Return(booksResponse(5, "d"), nil),
+	)
+
+	assert.Equal(t, []string{"a", "b", "x", "c", "d"}, collectIDs(t, client.AllBooks(context.Background(), "some.token", 2)))
+}
+
+func TestClient_AllBooks_LimitCapped(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+
+	// the server returns fewer books than requested
+	gomock.InOrder(
+		httpMock.EXPECT().Do(isBooksPage(t, 3, 0)).Return(booksResponse(5, "a", "b"), nil),
+		httpMock.EXPECT().Do(isBooksPage(t, 3, 2)).Return(booksResponse(5, "c", "d"), nil),
+		httpMock.EXPECT().Do(isBooksPage(t, 3, 4)).Return(booksResponse(5, "e"), nil),
+	)
+
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, collectIDs(t, client.AllBooks(context.Background(), "some.token", 3)))
+}
+
+func TestClient_AllBooks_Error(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+	errExpected := errors.New("something went wrong")
+
+	gomock.InOrder(
+		httpMock.EXPECT().Do(isBooksPage(t, 2, 0)).Return(booksResponse(5, "a", "b"), nil),
+		httpMock.EXPECT().Do(isBooksPage(t, 2, 2)).Return(nil, errExpected),
+	)
+
+	var (
+		ids  []string
+		errs []error
+	)
+
+	for b, err := range client.AllBooks(context.Background(), "some.token", 2) {
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		ids = append(ids, b.ID)
+	}
+
+	assert.Equal(t, []string{"a", "b"}, ids)
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[0], errExpected)
+}
+
+func isBooksPage(t *testing.T, limit, offset int) gomock.Matcher {
+	t.Helper()
+
+	return mock.MatchedBy(func(req *http.Request) bool {
+		o := ""
+		if offset > 0 {
+			o = strconv.Itoa(offset)
+		}
+
+		return req.URL.Path == "/api/v1.0/books" &&
+			req.URL.Query().Get("limit") == strconv.Itoa(limit) &&
+			req.URL.Query().Get("offset") == o
+	})
+}
+
+func booksResponse(total int, ids ...string) *http.Response {
+	type item struct {
+		ID string `json:"id"`
+	}
+
+	data := struct {
+		Total int    `json:"total"`
+		Items []item `json:"items"`
+	}{Total: total}
+
+	for _, id := range ids {
+		data.Items = append(data.Items, item{ID: id})
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(must(json.Marshal(data)))),
+	}
+}
+
+func collectIDs(t *testing.T, seq iter.Seq2[pbc.Book, error]) []string {
+	t.Helper()
+
+	var ids []string
+
+	for b, err := range seq {
+		require.NoError(t, err)
+
+		ids = append(ids, b.ID)
+	}
+
+	return ids
+}
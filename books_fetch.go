@@ -0,0 +1,171 @@
+package pocketbook_cloud_client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// DefaultFetchConcurrency is the number of pages requested at once when a non-positive one is passed.
+const DefaultFetchConcurrency = 4
+
+// FetchOptions configures FetchAllBooks.
+type FetchOptions struct {
+	// PageSize is the limit of books per request, DefaultPageSize if not positive.
+	PageSize int
+	// Concurrency is the number of pages requested at once, DefaultFetchConcurrency if not positive.
+	Concurrency int
+}
+
+// PageError is the failure of the page request.
+type PageError struct {
+	Offset int
+	Limit  int
+	Err    error
+}
+
+func (e PageError) Error() string {
+	return fmt.Sprintf("page offset=%d limit=%d: %v", e.Offset, e.Limit, e.Err)
+}
+
+func (e PageError) Unwrap() error {
+	return e.Err
+}
+
+// FetchError reports the pages failed by FetchAllBooks, the books of the rest pages are returned.
+type FetchError struct {
+	Pages []PageError
+}
+
+func (e *FetchError) Error() string {
+	msgs := make([]string, len(e.Pages))
+	for i, p := range e.Pages {
+		msgs[i] = p.Error()
+	}
+
+	return fmt.Sprintf("fetch books: %d pages failed: %s", len(e.Pages), strings.Join(msgs, "; "))
+}
+
+func (e *FetchError) Unwrap() []error {
+	errs := make([]error, len(e.Pages))
+	for i, p := range e.Pages {
+		errs[i] = p
+	}
+
+	return errs
+}
+
+// FetchAllBooks gets all books of the library requesting pages concurrently.
+// The first page is requested to learn the total, then the rest pages are requested in parallel.
+// Books are returned in the server order deduplicated by ID.
+// When the total of some page differs from the first one, the library has changed while fetching
+// and the pages are stale, so it is fetched again page by page like AllBooks follows the changes.
+// When some pages fail, the books of the others are returned along with *FetchError.
+func (c Client) FetchAllBooks(ctx context.Context, token string, opts FetchOptions) (Books, error) {
+	return fetchAllBooks(ctx, opts, func(ctx context.Context, limit, offset int) (Books, error) {
+		return c.Books(ctx, token, limit, offset)
+	})
+}
+
+func fetchAllBooks(ctx context.Context, opts FetchOptions, fetch booksPager) (Books, error) {
+	limit := opts.PageSize
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultFetchConcurrency
+	}
+
+	first, err := fetch(ctx, limit, 0)
+	if err != nil {
+		return Books{}, err
+	}
+
+	pages := make([][]Book, 1, (first.Total+limit-1)/limit+1)
+	pages[0] = first.Books
+
+	for offset := limit; offset < first.Total; offset += limit {
+		pages = append(pages, nil)
+	}
+
+	errs := make([]error, len(pages))
+	totals := make([]int, len(pages))
+	sem := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+
+	for i := 1; i < len(pages); i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+
+				return
+			}
+
+			page, err := fetch(ctx, limit, i*limit)
+			pages[i], totals[i], errs[i] = page.Books, page.Total, err
+		}()
+	}
+
+	wg.Wait()
+
+	for i := 1; i < len(pages); i++ {
+		if errs[i] == nil && totals[i] != first.Total {
+			return fetchChangedBooks(ctx, limit, fetch)
+		}
+	}
+
+	books := Books{Total: first.Total}
+	seen := make(map[string]struct{}, first.Total)
+
+	var failed []PageError
+
+	for i, page := range pages {
+		if errs[i] != nil {
+			failed = append(failed, PageError{Offset: i * limit, Limit: limit, Err: errs[i]})
+
+			continue
+		}
+
+		for _, b := range page {
+			if _, ok := seen[b.ID]; ok {
+				continue
+			}
+
+			seen[b.ID] = struct{}{}
+			books.Books = append(books.Books, b)
+		}
+	}
+
+	if len(failed) > 0 {
+		return books, &FetchError{Pages: failed}
+	}
+
+	return books, nil
+}
+
+// fetchChangedBooks gets all books of the library changed while it was fetched concurrently.
+func fetchChangedBooks(ctx context.Context, limit int, fetch booksPager) (Books, error) {
+	var books Books
+
+	for b, err := range allBooks(ctx, limit, fetch) {
+		if err != nil {
+			return books, err
+		}
+
+		books.Books = append(books.Books, b)
+		books.Total++
+	}
+
+	return books, nil
+}
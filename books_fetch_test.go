@@ -0,0 +1,176 @@
+package pocketbook_cloud_client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	pbc "github.com/micronull/pocketbook-cloud-client"
+	"github.com/micronull/pocketbook-cloud-client/mocks"
+)
+
+func TestClient_FetchAllBooks(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+
+	var inflight, maxInflight atomic.Int32
+
+	pages := map[string][]string{
+		"":  {"a", "b"},
+		"2": {"b", "c"}, // "x" was added to the start during fetching
+		"4": {"d", "e"},
+		"6": {"f", "g"},
+		"8": {"h"},
+	}
+
+	httpMock.EXPECT().
+		Do(gomock.Any()).
+		DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			n := inflight.Add(1)
+			defer inflight.Add(-1)
+
+			for {
+				m := maxInflight.Load()
+				if n <= m || maxInflight.CompareAndSwap(m, n) {
+					break
+				}
+			}
+
+			time.Sleep(10 * time.Millisecond)
+
+			assert.Equal(t, "2", req.URL.Query().Get("limit"))
+
+			return booksResponse(9, pages[req.URL.Query().Get("offset")]...), nil
+		}).
+		Times(5)
+
+	got, err := client.FetchAllBooks(context.Background(), "some.token", pbc.FetchOptions{PageSize: 2, Concurrency: 2})
+	require.NoError(t, err)
+
+	assert.Equal(t, 9, got.Total)
+
+	ids := make([]string, len(got.Books))
+	for i, b := range got.Books {
+		ids[i] = b.ID
+	}
+
+	assert.Equal(t, []string{"a", "b", "c", "d", "e", "f", "g", "h"}, ids)
+	assert.LessOrEqual(t, maxInflight.Load(), int32(2))
+}
+
+func TestClient_FetchAllBooks_Changed(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		changed []string
+	}{
+		{name: "shrunk", changed: []string{"a", "c", "d", "e", "f"}},
+		{name: "grown", changed: []string{"a", "b", "c", "d", "e", "f", "g", "h"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrlMock := gomock.NewController(t)
+			httpMock := mocks.NewMockDoer(ctrlMock)
+			client := pbc.New(pbc.WithHTTPClient(httpMock))
+
+			var (
+				mu      sync.Mutex
+				library = []string{"a", "b", "c", "d", "e", "f"}
+			)
+
+			httpMock.EXPECT().
+				Do(gomock.Any()).
+				DoAndReturn(func(req *http.Request) (*http.Response, error) {
+					mu.Lock()
+					defer mu.Unlock()
+
+					offset, _ := strconv.Atoi(req.URL.Query().Get("offset"))
+					page := library[min(offset, len(library)):min(offset+2, len(library))]
+					rsp := booksResponse(len(library), page...)
+
+					// the library changes right after the first page
+					library = tt.changed
+
+					return rsp, nil
+				}).
+				AnyTimes()
+
+			got, err := client.FetchAllBooks(context.Background(), "some.token", pbc.FetchOptions{PageSize: 2, Concurrency: 2})
+			require.NoError(t, err)
+
+			ids := make([]string, len(got.Books))
+			for i, b := range got.Books {
+				ids[i] = b.ID
+			}
+
+			assert.Equal(t, tt.changed, ids)
+			assert.Equal(t, len(tt.changed), got.Total)
+		})
+	}
+}
+
+func TestClient_FetchAllBooks_PartialFailure(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+	errExpected := errors.New("something went wrong")
+
+	httpMock.EXPECT().
+		Do(gomock.Any()).
+		DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			switch req.URL.Query().Get("offset") {
+			case "":
+				return booksResponse(5, "a", "b"), nil
+			case "2":
+				return nil, errExpected
+			}
+
+			return booksResponse(5, "e"), nil
+		}).
+		Times(3)
+
+	got, err := client.FetchAllBooks(context.Background(), "some.token", pbc.FetchOptions{PageSize: 2})
+	require.ErrorIs(t, err, errExpected)
+
+	var fetchErr *pbc.FetchError
+	require.ErrorAs(t, err, &fetchErr)
+	require.Len(t, fetchErr.Pages, 1)
+
+	assert.Equal(t, 2, fetchErr.Pages[0].Offset)
+	assert.Equal(t, 2, fetchErr.Pages[0].Limit)
+	assert.Len(t, got.Books, 3)
+}
+
+func TestClient_FetchAllBooks_FirstPageError(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+	errExpected := errors.New("something went wrong")
+
+	httpMock.EXPECT().
+		Do(gomock.Any()).
+		Return(nil, errExpected)
+
+	_, err := client.FetchAllBooks(context.Background(), "some.token", pbc.FetchOptions{})
+	require.ErrorIs(t, err, errExpected)
+}
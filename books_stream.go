@@ -0,0 +1,134 @@
+package pocketbook_cloud_client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// StreamBooks gets the page of books like Books, but decodes the response item by item
+// and passes every book to fn instead of keeping the whole page in memory.
+// The error returned by fn stops decoding and is returned wrapped.
+// The returned total is the number of books in the library.
+func (c Client) StreamBooks(ctx context.Context, token string, limit, offset int, fn func(Book) error) (int, error) {
+	rsp, err := c.do(OperationBooks, c.booksRequest(ctx, token, limit, offset))
+	if err != nil {
+		return 0, fmt.Errorf("stream books: %w", err)
+	}
+
+	defer func() { _ = rsp.Body.Close() }()
+
+	total, err := c.streamBooks(c.limitBody(rsp), fn)
+	if err != nil {
+		return total, fmt.Errorf("stream books: %w", err)
+	}
+
+	return total, nil
+}
+
+// streamBooks walks the tokens of the books response, decoding only one item at a time.
+func (c Client) streamBooks(r io.Reader, fn func(Book) error) (int, error) {
+	dec := json.NewDecoder(r)
+
+	if err := expectDelim(dec, '{'); err != nil {
+		return 0, err
+	}
+
+	var total lenientInt
+
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return int(total), fmt.Errorf("unmarshal response body: %w", err)
+		}
+
+		key, _ := t.(string)
+
+		switch key {
+		case "items":
+			if err = c.streamItems(dec, fn); err != nil {
+				return int(total), err
+			}
+		case "total":
+			var raw json.RawMessage
+
+			if err = dec.Decode(&raw); err != nil {
+				return int(total), fmt.Errorf("unmarshal response body: %w", err)
+			}
+
+			if err = c.decodeAt(OperationBooks, key, raw, &total); err != nil {
+				return int(total), err
+			}
+		default:
+			var raw json.RawMessage
+
+			if err = dec.Decode(&raw); err != nil {
+				return int(total), fmt.Errorf("unmarshal response body: %w", err)
+			}
+
+			if c.strict == nil {
+				continue
+			}
+
+			issue := DecodeIssue{Operation: OperationBooks, Path: key, Kind: DecodeIssueUnknownField}
+			if err = c.strict(issue); err != nil {
+				return int(total), err
+			}
+		}
+	}
+
+	if err := expectDelim(dec, '}'); err != nil {
+		return int(total), err
+	}
+
+	return int(total), nil
+}
+
+func (c Client) streamItems(dec *json.Decoder, fn func(Book) error) error {
+	t, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("unmarshal response body: %w", err)
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	if t != json.Delim('[') {
+		return fmt.Errorf("unmarshal response body: items: unexpected %v", t)
+	}
+
+	for i := 0; dec.More(); i++ {
+		var raw json.RawMessage
+
+		if err = dec.Decode(&raw); err != nil {
+			return fmt.Errorf("unmarshal response body: %w", err)
+		}
+
+		var item bookItem
+
+		if err = c.decodeAt(OperationBooks, fmt.Sprintf("items[%d]", i), raw, &item); err != nil {
+			return err
+		}
+
+		if err = fn(mappingBook(item)); err != nil {
+			return err
+		}
+	}
+
+	return expectDelim(dec, ']')
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	t, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("unmarshal response body: %w", err)
+	}
+
+	if t != delim {
+		return fmt.Errorf("unmarshal response body: expected %v, got %v", delim, t)
+	}
+
+	return nil
+}
@@ -0,0 +1,193 @@
+package pocketbook_cloud_client_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	pbc "github.com/micronull/pocketbook-cloud-client"
+	"github.com/micronull/pocketbook-cloud-client/mocks"
+)
+
+func TestClient_StreamBooks(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+
+	httpMock.EXPECT().
+		Do(gomock.Any()).
+		DoAndReturn(func(*http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: must(testdata.Open("testdata/books.json"))}, nil
+		}).
+		Times(2)
+
+	expected, err := client.Books(context.Background(), "some.token", 2, 0)
+	require.NoError(t, err)
+
+	var streamed []pbc.Book
+
+	total, err := client.StreamBooks(context.Background(), "some.token", 2, 0, func(b pbc.Book) error {
+		streamed = append(streamed, b)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, expected.Total, total)
+	assert.Equal(t, expected.Books, streamed)
+}
+
+func TestClient_StreamBooks_Stop(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+
+	httpMock.EXPECT().
+		Do(gomock.Any()).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: must(testdata.Open("testdata/books.json"))}, nil)
+
+	errStop := errors.New("stop")
+	calls := 0
+
+	_, err := client.StreamBooks(context.Background(), "some.token", 2, 0, func(pbc.Book) error {
+		calls++
+
+		return errStop
+	})
+
+	require.ErrorIs(t, err, errStop)
+	assert.Equal(t, 1, calls)
+}
+
+func TestClient_StreamBooks_Strict(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+
+	var issues []pbc.DecodeIssue
+
+	client := pbc.New(pbc.WithHTTPClient(httpMock), pbc.WithStrictDecoding(func(i pbc.DecodeIssue) error {
+		issues = append(issues, i)
+
+		return nil
+	}))
+
+	httpMock.EXPECT().
+		Do(gomock.Any()).
+		DoAndReturn(func(*http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(driftedBooks))}, nil
+		}).
+		Times(2)
+
+	_, err := client.Books(context.Background(), "some.token", 1, 0)
+	require.NoError(t, err)
+
+	expected := issues
+	issues = nil
+
+	_, err = client.StreamBooks(context.Background(), "some.token", 1, 0, func(pbc.Book) error { return nil })
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, issues)
+	assert.ElementsMatch(t, expected, issues)
+}
+
+func TestClient_MaxResponseSize(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock), pbc.WithMaxResponseSize(1024))
+
+	httpMock.EXPECT().
+		Do(gomock.Any()).
+		DoAndReturn(func(*http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: must(testdata.Open("testdata/books.json"))}, nil
+		}).
+		Times(2)
+
+	_, err := client.Books(context.Background(), "some.token", 2, 0)
+	require.ErrorIs(t, err, pbc.ErrResponseTooLarge)
+
+	_, err = client.StreamBooks(context.Background(), "some.token", 2, 0, func(pbc.Book) error { return nil })
+	require.ErrorIs(t, err, pbc.ErrResponseTooLarge)
+}
+
+// largeBooks is the response of 10k books generated from the testdata.
+var largeBooks = sync.OnceValue(func() []byte {
+	var data struct {
+		Items []json.RawMessage `json:"items"`
+	}
+
+	if err := json.NewDecoder(must(testdata.Open("testdata/books.json"))).Decode(&data); err != nil {
+		panic(err)
+	}
+
+	const total = 10_000
+
+	buf := bytes.NewBufferString(`{"total":10000,"items":[`)
+
+	for i := range total {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		buf.Write(data.Items[i%len(data.Items)])
+	}
+
+	buf.WriteString(`]}`)
+
+	return buf.Bytes()
+})
+
+func benchmarkClient(b *testing.B) *pbc.Client {
+	b.Helper()
+
+	body := largeBooks()
+
+	return pbc.New(pbc.WithHTTPClient(pbc.DoerFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(body))}, nil
+	})))
+}
+
+func BenchmarkClient_Books(b *testing.B) {
+	client := benchmarkClient(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for range b.N {
+		if _, err := client.Books(context.Background(), "some.token", 10_000, 0); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkClient_StreamBooks(b *testing.B) {
+	client := benchmarkClient(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for range b.N {
+		_, err := client.StreamBooks(context.Background(), "some.token", 10_000, 0, func(pbc.Book) error { return nil })
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
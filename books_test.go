@@ -3,9 +3,11 @@ package pocketbook_cloud_client_test
 import (
 	"context"
 	"errors"
+	"io"
 	"math/rand/v2"
 	"net/http"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -50,28 +52,28 @@ func TestClient_Books(t *testing.T) {
 				ID:          "76220203",
 				Path:        "/voina-i-mir.epub",
 				Title:       "Война и мир",
-				MimeType:    "application/epub+zip",
+				MimeType:    pbc.MimeTypeEPUB,
 				CreatedAt:   time.Date(2024, time.December, 11, 15, 41, 28, 0, time.UTC),
 				Purchased:   false,
 				Bytes:       2039555,
 				ClientMtime: time.Date(2024, time.December, 10, 15, 41, 28, 0, time.UTC),
 				FastHash:    "5c624ec0db399a8f1b99eddabf1e22c1",
 				Favorite:    false,
-				ReadStatus:  "read",
+				ReadStatus:  pbc.ReadStatusRead,
 				Link:        "https://cloud.pocketbook.digital/api/v1.0/files/voina-i-mir.epub?fast_hash=5c624ec0db399a8f1b99eddabf1e22c1&access_token=some.token",
 				HasLinks:    true,
-				Format:      "epub",
+				Format:      pbc.FormatEPUB,
 				Md5Hash:     "WW/v6YxXMXC2Zi4a5x71oA==",
 				Mtime:       time.Date(2024, time.December, 11, 14, 42, 17, 0, time.UTC),
 				Name:        "voina-i-mir.epub",
 				ReadPercent: 100,
-				Percent:     "100",
 				IsDrm:       false,
 				IsLcp:       false,
 				IsAudioBook: false,
 				MetaData: pbc.BookMetaData{
-					Title:   "Война и мир",
-					Authors: "Толстой Л.Н.",
+					Title:       "Война и мир",
+					TrackNumber: pbc.Null[int](),
+					Authors:     "Толстой Л.Н.",
 					Cover: []pbc.BookCover{
 						{
 							Width:  300,
@@ -85,60 +87,67 @@ func TestClient_Books(t *testing.T) {
 						},
 					},
 					Lang:        "ru",
-					Publisher:   "ДА!Медиа",
-					Updated:     time.Date(2024, time.December, 11, 15, 41, 31, 0, time.UTC),
-					Year:        2014,
-					Isbn:        "9785447237509",
+					Publisher:   pbc.Some("ДА!Медиа"),
+					Size:        pbc.Null[int64](),
+					Duration:    pbc.Null[int](),
+					Updated:     pbc.Some(time.Date(2024, time.December, 11, 15, 41, 31, 0, time.UTC)),
+					Year:        pbc.Some(2014),
+					Isbn:        pbc.Some("9785447237509"),
+					Series:      pbc.Null[string](),
+					Annotation:  pbc.Null[string](),
 					BookId:      []string{"urn:uuid:95f0109f-dacc-48fc-9bb5-9456c37803b7"},
 					FixedLayout: false,
+					SeriesOrd:   pbc.Null[int](),
 				},
 				Position: pbc.BookPosition{
-					Pointer:    "some_position_pointer",
-					PointerPb:  "some_position_pointer_pb",
-					Percent:    10,
-					Page:       "1",
-					PagesTotal: 999,
-					Updated:    time.Time{},
-					Offs:       0,
+					Pointer:    pbc.Some("some_position_pointer"),
+					PointerPb:  pbc.Some("some_position_pointer_pb"),
+					Percent:    pbc.Some(10),
+					Page:       pbc.Some("1"),
+					PagesTotal: pbc.Some(999),
+					Updated:    pbc.Null[time.Time](),
+					Offs:       pbc.Some(0),
 				},
 				ReadPosition: pbc.BookReadPosition{
-					Pointer:    "",
-					PointerPb:  "",
-					Percent:    0,
-					Page:       "",
-					PagesTotal: 0,
-					Updated:    time.Time{},
-					Offs:       0,
+					Pointer:    pbc.Null[string](),
+					PointerPb:  pbc.Null[string](),
+					Percent:    pbc.Some(0),
+					Page:       pbc.Null[string](),
+					PagesTotal: pbc.Some(0),
+					Updated:    pbc.Null[time.Time](),
+					Offs:       pbc.Some(0),
 				},
-				Action:     "create",
+				Action:     pbc.ActionCreate,
 				ActionDate: time.Date(2024, time.November, 11, 15, 41, 28, 0, time.UTC),
 			},
 			{
 				ID:          "76220340",
 				Path:        "/puteshestvie-iz-peterburga-v-moskvu.epub",
 				Title:       "Путешествие из Петербурга в Москву",
-				MimeType:    "application/epub+zip",
+				MimeType:    pbc.MimeTypeEPUB,
 				CreatedAt:   time.Date(2024, time.December, 11, 15, 44, 46, 0, time.UTC),
 				Purchased:   false,
+				ResourceID:  "some.resource.id",
 				Bytes:       292816,
 				ClientMtime: time.Date(2024, time.December, 11, 15, 44, 45, 0, time.UTC),
+				Collections: []string{"Классика", "Прочитать"},
 				FastHash:    "01882d1bb27a52caba5d8459c80db321",
 				Favorite:    true,
-				ReadStatus:  "reading",
+				ReadStatus:  pbc.ReadStatusReading,
 				Link:        "https://cloud.pocketbook.digital/api/v1.0/files/puteshestvie-iz-peterburga-v-moskvu.epub?fast_hash=01882d1bb27a52caba5d8459c80db321&access_token=some.token",
 				HasLinks:    true,
-				Format:      "epub",
+				Format:      pbc.FormatEPUB,
 				Md5Hash:     "6gDHcYaOMWA9qoovZeSUZw==",
 				Mtime:       time.Date(2024, time.December, 11, 15, 44, 50, 0, time.UTC),
 				Name:        "puteshestvie-iz-peterburga-v-moskvu.epub",
 				ReadPercent: 4,
-				Percent:     "4",
 				IsDrm:       false,
 				IsLcp:       false,
 				IsAudioBook: false,
 				MetaData: pbc.BookMetaData{
-					Title:   "Путешествие из Петербурга в Москву",
-					Authors: "Радищев А.Н.",
+					Title:       "Путешествие из Петербурга в Москву",
+					TrackNumber: pbc.Some(2),
+					Authors:     "Радищев А.Н.",
 					Cover: []pbc.BookCover{
 						{
 							Width:  256,
@@ -151,39 +160,49 @@ func TestClient_Books(t *testing.T) {
 							Path:   "https://cloud.pocketbook.digital/api/v1.0/fileops/cover/puteshestvie-iz-peterburga-v-moskvu.epub.cover_b.jpg?fast_hash=01882d1bb27a52caba5d8459c80db321&access_token=some.token",
 						},
 					},
+					Genres:      []string{"prose_rus_classic", "nonf_publicism"},
 					Lang:        "ru",
-					Publisher:   "",
-					Updated:     time.Date(2024, time.December, 11, 15, 44, 50, 0, time.UTC),
-					Year:        2019,
-					Isbn:        "",
+					Publisher:   pbc.Null[string](),
+					Size:        pbc.Some[int64](292816),
+					Duration:    pbc.Some(5400),
+					Updated:     pbc.Some(time.Date(2024, time.December, 11, 15, 44, 50, 0, time.UTC)),
+					Year:        pbc.Some(2019),
+					Isbn:        pbc.Null[string](),
+					Series:      pbc.Some("Русская классика"),
+					Annotation:  pbc.Some("Книга о путешествии из Петербурга в Москву."),
 					BookId:      []string{"urn:uuid:8f743510-6b3e-4bbe-9d3f-447ef0788ad0"},
 					FixedLayout: false,
+					SeriesOrd:   pbc.Some(3),
 				},
 				Position: pbc.BookPosition{
-					Pointer:    "",
-					PointerPb:  "",
-					Percent:    0,
-					Page:       "",
-					PagesTotal: 0,
-					Updated:    time.Time{},
-					Offs:       0,
+					Pointer:    pbc.Null[string](),
+					PointerPb:  pbc.Null[string](),
+					Percent:    pbc.Some(0),
+					Page:       pbc.Null[string](),
+					PagesTotal: pbc.Some(0),
+					Updated:    pbc.Null[time.Time](),
+					Offs:       pbc.Some(0),
 				},
 				ReadPosition: pbc.BookReadPosition{
-					Pointer:    "",
-					PointerPb:  "",
-					Percent:    0,
-					Page:       "",
-					PagesTotal: 0,
-					Updated:    time.Time{},
-					Offs:       0,
+					Pointer:    pbc.Some("some_read_pointer"),
+					PointerPb:  pbc.Some("some_read_pointer_pb"),
+					Percent:    pbc.Some(4),
+					Page:       pbc.Some("12"),
+					PagesTotal: pbc.Some(300),
+					Updated:    pbc.Some(time.Date(2024, time.December, 11, 16, 0, 0, 0, time.UTC)),
+					Offs:       pbc.Some(0),
 				},
-				Action:     "create",
+				Action:     pbc.ActionCreate,
 				ActionDate: time.Date(2024, time.December, 11, 15, 44, 46, 0, time.UTC),
 			},
 		},
 	}
 
 	assert.Equal(t, expected, books)
+
+	assert.True(t, books.Books[0].HasPosition())
+	assert.False(t, books.Books[0].HasReadPosition(), "never read")
+	assert.True(t, books.Books[1].HasReadPosition(), "read at offset 0")
 }
 
 func TestClient_Books_Error_StatusCode_NoOk(t *testing.T) {
@@ -222,3 +241,58 @@ func TestClient_Books_Error(t *testing.T) {
 	_, err := client.Books(context.Background(), "", 0, 0)
 	require.ErrorIs(t, err, errExpected)
 }
+
+func TestClient_Books_LegacyPercent(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+
+	httpMock.EXPECT().
+		Do(gomock.Any()).
+		Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"total":1,"items":[{"id":"1","percent":"42"}]}`)),
+		}, nil)
+
+	books, err := client.Books(context.Background(), "some.token", 1, 0)
+	require.NoError(t, err)
+	require.Len(t, books.Books, 1)
+
+	assert.Equal(t, 42, books.Books[0].ReadPercent)
+}
+
+func TestClient_Books_MetaDataOptional(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+
+	httpMock.EXPECT().
+		Do(gomock.Any()).
+		Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`{"total":3,"items":[
+				{"id":"1","metadata":{}},
+				{"id":"2","metadata":{"updated":null,"year":null}},
+				{"id":"3","metadata":{"updated":"0001-01-01T00:00:00Z","year":0}}
+			]}`)),
+		}, nil)
+
+	books, err := client.Books(context.Background(), "some.token", 3, 0)
+	require.NoError(t, err)
+	require.Len(t, books.Books, 3)
+
+	missing, null, zero := books.Books[0].MetaData, books.Books[1].MetaData, books.Books[2].MetaData
+
+	assert.True(t, missing.Updated.Missing())
+	assert.True(t, missing.Year.Missing())
+
+	assert.True(t, null.Updated.IsNull())
+	assert.True(t, null.Year.IsNull())
+
+	assert.Equal(t, pbc.Some(time.Time{}), zero.Updated)
+	assert.Equal(t, pbc.Some(0), zero.Year)
+}
@@ -1,14 +1,19 @@
-//go:generate mockgen -source $GOFILE -destination mocks/$GOFILE -package mocks -mock_names doer=Doer
+//go:generate mockgen -source $GOFILE -destination mocks/$GOFILE -package mocks -mock_names Doer=MockDoer
 package pocketbook_cloud_client
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
 )
 
-type doer interface {
+// Doer sends http requests, *http.Client implements it.
+type Doer interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
@@ -19,15 +24,23 @@ const (
 
 	login = "auth/login"
 	books = "books"
+	files = "files"
 )
 
 type Client struct {
-	http         doer
+	http         Doer
 	scheme       string
 	host         string
 	path         string
 	clientID     string
 	clientSecret string
+	retry        RetryPolicy
+	limits       *rateLimits
+	middlewares  []Middleware
+	logger       *slog.Logger
+	observer     Observer
+	strict       func(DecodeIssue) error
+	maxResponse  int64
 }
 
 func New(opts ...Option) *Client {
@@ -42,22 +55,26 @@ func New(opts ...Option) *Client {
 		opt(c)
 	}
 
+	if c.logger != nil {
+		c.http = c.logging(c.logger)(c.http)
+	}
+
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		c.http = c.middlewares[i](c.http)
+	}
+
 	return c
 }
 
-func (c Client) req(req *http.Request) ([]byte, error) {
-	rsp, err := c.http.Do(req)
+func (c Client) req(op string, req *http.Request) ([]byte, error) {
+	rsp, err := c.do(op, req)
 	if err != nil {
-		return nil, fmt.Errorf("do: %w", err)
-	}
-
-	if rsp.StatusCode != http.StatusOK {
-		return nil, httpStatusError{rsp.StatusCode}
+		return nil, err
 	}
 
 	defer func() { _ = rsp.Body.Close() }()
 
-	body, err := io.ReadAll(rsp.Body)
+	body, err := io.ReadAll(c.limitBody(rsp))
 	if err != nil {
 		return nil, fmt.Errorf("read response body: %w", err)
 	}
@@ -65,6 +82,128 @@ func (c Client) req(req *http.Request) ([]byte, error) {
 	return body, nil
 }
 
+// limitBody returns the response body limited by the maximum response size.
+func (c Client) limitBody(rsp *http.Response) io.Reader {
+	if c.maxResponse <= 0 {
+		return rsp.Body
+	}
+
+	return &limitedReader{r: rsp.Body, n: c.maxResponse}
+}
+
+// limitedReader fails with ErrResponseTooLarge, unlike io.LimitedReader silently stopping at the limit.
+type limitedReader struct {
+	r io.Reader
+	n int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.n <= 0 {
+		var probe [1]byte
+
+		n, err := l.r.Read(probe[:])
+		if n > 0 {
+			return 0, ErrResponseTooLarge
+		}
+
+		return 0, err
+	}
+
+	if int64(len(p)) > l.n {
+		p = p[:l.n]
+	}
+
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+
+	return n, err
+}
+
+// do sends the request, retrying it according to the retry policy.
+// The returned response is successful, see succeeded, the caller must close its body.
+func (c Client) do(op string, req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	for attempt := 1; ; attempt++ {
+		r := req
+
+		if attempt > 1 {
+			var err error
+			if r, err = rewind(req); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := c.limits.wait(r, c.endpoint(r.URL)); err != nil {
+			return nil, err
+		}
+
+		r = c.startAttempt(op, r)
+		start := time.Now()
+
+		rsp, err := c.http.Do(r)
+		if err == nil && rsp.Body == nil {
+			rsp.Body = http.NoBody
+		}
+
+		c.observe(r.Context(), op, r, attempt, start, rsp, err)
+
+		if err == nil && succeeded(r, rsp) {
+			return rsp, nil
+		}
+
+		retry := c.retry.retryable(attempt, rsp, err) && replayable(req) && ctx.Err() == nil
+
+		if err != nil {
+			err = fmt.Errorf("do: %w", err)
+			rsp = nil
+		} else {
+			err = c.apiError(req, rsp)
+			_ = rsp.Body.Close()
+		}
+
+		if !retry {
+			return nil, err
+		}
+
+		d, ok := c.retry.backoff(attempt, rsp)
+		if !ok {
+			return nil, err
+		}
+
+		if serr := sleep(ctx, d); serr != nil {
+			return nil, errors.Join(err, serr)
+		}
+	}
+}
+
+// succeeded reports whether the response has 200 OK, 201 Created or 204 No Content status code,
+// 206 Partial Content is accepted only as the response to the Range request.
+func succeeded(req *http.Request, rsp *http.Response) bool {
+	switch rsp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+		return true
+	case http.StatusPartialContent:
+		return req.Header.Get("Range") != ""
+	}
+
+	return false
+}
+
+// maxErrorBodySize limits the error body kept in APIError.
+const maxErrorBodySize = 64 << 10
+
+func (c Client) apiError(req *http.Request, rsp *http.Response) *APIError {
+	body, _ := io.ReadAll(io.LimitReader(rsp.Body, maxErrorBodySize))
+
+	return newAPIError(c.endpoint(req.URL), rsp, body)
+}
+
+// endpoint returns the path of the url relative to the API root.
+func (c Client) endpoint(u *url.URL) string {
+	return strings.TrimPrefix(u.Path, c.path)
+}
+
 func (c Client) url(endpoint string) *url.URL {
 	u := &url.URL{
 		Scheme: c.scheme,
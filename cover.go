@@ -0,0 +1,132 @@
+package pocketbook_cloud_client
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// ErrNoCover is returned when the book has no covers, it also matches ErrNotFound.
+var ErrNoCover = fmt.Errorf("book has no cover: %w", ErrNotFound)
+
+// CoverSpec is the target size of the cover, zero dimensions are not constrained.
+type CoverSpec struct {
+	Width  int
+	Height int
+}
+
+// CoverImage is the streamed cover, the caller must close its body.
+type CoverImage struct {
+	BookCover
+	// ContentType is the media type of the image, e.g. "image/jpeg".
+	ContentType string
+	Body        io.ReadCloser
+}
+
+// Cover downloads the cover of the book best fitting the spec:
+// the smallest one covering the target size, otherwise the largest one.
+func (c Client) Cover(ctx context.Context, token string, book Book, spec CoverSpec) (*CoverImage, error) {
+	cover, ok := chooseCover(book.MetaData.Cover, spec)
+	if !ok {
+		return nil, fmt.Errorf("get cover of %s: %w", book.Name, ErrNoCover)
+	}
+
+	req, err := c.coverRequest(ctx, token, cover)
+	if err != nil {
+		return nil, fmt.Errorf("get cover of %s: %w", book.Name, err)
+	}
+
+	rsp, err := c.do(OperationCover, req)
+	if err != nil {
+		return nil, fmt.Errorf("get cover of %s: %w", book.Name, err)
+	}
+
+	body := bufio.NewReader(rsp.Body)
+
+	return &CoverImage{
+		BookCover:   cover,
+		ContentType: coverContentType(rsp.Header.Get("Content-Type"), body),
+		Body: struct {
+			io.Reader
+			io.Closer
+		}{body, rsp.Body},
+	}, nil
+}
+
+func (c Client) coverRequest(ctx context.Context, token string, cover BookCover) (*http.Request, error) {
+	u, err := c.url("/").Parse(cover.Path)
+	if err != nil {
+		return nil, fmt.Errorf("parse cover path: %w", err)
+	}
+
+	req := &http.Request{
+		Method: http.MethodGet,
+		URL:    u,
+		Body:   http.NoBody,
+		Header: http.Header{},
+	}
+
+	// The token is not sent to the foreign host, the path is expected to be signed.
+	if u.Host == c.host {
+		req.Header.Set("Authorization", string(TokenTypeBearer)+" "+token)
+
+		if q := u.Query(); q.Has("access_token") {
+			q.Set("access_token", token)
+			u.RawQuery = q.Encode()
+		}
+	}
+
+	return req.WithContext(ctx), nil
+}
+
+func chooseCover(covers []BookCover, spec CoverSpec) (BookCover, bool) {
+	var (
+		best, largest BookCover
+		found         bool
+	)
+
+	for i, cover := range covers {
+		if i == 0 || area(cover) > area(largest) {
+			largest = cover
+		}
+
+		if cover.Width < spec.Width || cover.Height < spec.Height {
+			continue
+		}
+
+		if !found || area(cover) < area(best) {
+			best, found = cover, true
+		}
+	}
+
+	if found {
+		return best, true
+	}
+
+	return largest, len(covers) > 0
+}
+
+func area(cover BookCover) int {
+	return cover.Width * cover.Height
+}
+
+// coverContentType returns the image type of the header, sniffing the body when the header is not an image.
+func coverContentType(header string, body *bufio.Reader) string {
+	if mt, _, err := mime.ParseMediaType(header); err == nil && isImage(mt) {
+		return mt
+	}
+
+	head, _ := body.Peek(512)
+
+	mt, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+
+	return mt
+}
+
+func isImage(mediaType string) bool {
+	return strings.HasPrefix(mediaType, "image/")
+}
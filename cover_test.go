@@ -0,0 +1,141 @@
+package pocketbook_cloud_client_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	pbc "github.com/micronull/pocketbook-cloud-client"
+	"github.com/micronull/pocketbook-cloud-client/mocks"
+)
+
+const jpegHead = "\xff\xd8\xff\xe0\x00\x10JFIF\x00"
+
+func coverBook() pbc.Book {
+	return pbc.Book{
+		Name: "voina-i-mir.epub",
+		MetaData: pbc.BookMetaData{
+			Cover: []pbc.BookCover{
+				{Width: 300, Height: 291, Path: "https://cloud.pocketbook.digital/api/v1.0/fileops/cover/voina-i-mir.epub.cover_s.jpg?access_token=old.token"},
+				{Width: 600, Height: 582, Path: "https://cloud.pocketbook.digital/api/v1.0/fileops/cover/voina-i-mir.epub.cover_b.jpg?access_token=old.token"},
+			},
+		},
+	}
+}
+
+func TestClient_Cover(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		spec pbc.CoverSpec
+		path string
+	}{
+		{name: "any", spec: pbc.CoverSpec{}, path: "/api/v1.0/fileops/cover/voina-i-mir.epub.cover_s.jpg"},
+		{name: "small", spec: pbc.CoverSpec{Width: 200, Height: 200}, path: "/api/v1.0/fileops/cover/voina-i-mir.epub.cover_s.jpg"},
+		{name: "big", spec: pbc.CoverSpec{Width: 400}, path: "/api/v1.0/fileops/cover/voina-i-mir.epub.cover_b.jpg"},
+		{name: "larger than any", spec: pbc.CoverSpec{Width: 1000, Height: 1000}, path: "/api/v1.0/fileops/cover/voina-i-mir.epub.cover_b.jpg"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrlMock := gomock.NewController(t)
+			httpMock := mocks.NewMockDoer(ctrlMock)
+			client := pbc.New(pbc.WithHTTPClient(httpMock))
+
+			httpMock.EXPECT().
+				Do(mock.MatchedBy(func(req *http.Request) bool {
+					return isAllTrue(
+						assert.Equal(t, http.MethodGet, req.Method),
+						assert.Equal(t, tt.path, req.URL.Path),
+						assert.Equal(t, "access_token=some.token", req.URL.RawQuery),
+						assert.Equal(t, "Bearer some.token", req.Header.Get("Authorization")),
+					)
+				})).
+				Return(&http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"image/jpeg"}},
+					Body:       io.NopCloser(strings.NewReader(jpegHead)),
+				}, nil)
+
+			img, err := client.Cover(context.Background(), "some.token", coverBook(), tt.spec)
+			require.NoError(t, err)
+
+			defer func() { _ = img.Body.Close() }()
+
+			assert.Equal(t, "image/jpeg", img.ContentType)
+			assert.Equal(t, jpegHead, string(must(io.ReadAll(img.Body))))
+		})
+	}
+}
+
+func TestClient_Cover_DetectContentType(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+
+	httpMock.EXPECT().
+		Do(gomock.Any()).
+		Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/octet-stream"}},
+			Body:       io.NopCloser(strings.NewReader(jpegHead)),
+		}, nil)
+
+	img, err := client.Cover(context.Background(), "some.token", coverBook(), pbc.CoverSpec{})
+	require.NoError(t, err)
+
+	defer func() { _ = img.Body.Close() }()
+
+	assert.Equal(t, "image/jpeg", img.ContentType)
+	assert.Equal(t, jpegHead, string(must(io.ReadAll(img.Body))))
+}
+
+func TestClient_Cover_ForeignHost(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+
+	book := pbc.Book{MetaData: pbc.BookMetaData{Cover: []pbc.BookCover{
+		{Width: 300, Height: 300, Path: "https://cdn.example.com/cover.jpg?signature=abc"},
+	}}}
+
+	httpMock.EXPECT().
+		Do(mock.MatchedBy(func(req *http.Request) bool {
+			return isAllTrue(
+				assert.Equal(t, "cdn.example.com", req.URL.Host),
+				assert.Equal(t, "signature=abc", req.URL.RawQuery),
+				assert.Empty(t, req.Header.Get("Authorization")),
+			)
+		})).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(jpegHead))}, nil)
+
+	img, err := client.Cover(context.Background(), "some.token", book, pbc.CoverSpec{})
+	require.NoError(t, err)
+
+	_ = img.Body.Close()
+}
+
+func TestClient_Cover_NoCover(t *testing.T) {
+	t.Parallel()
+
+	client := pbc.New(pbc.WithHTTPClient(mocks.NewMockDoer(gomock.NewController(t))))
+
+	_, err := client.Cover(context.Background(), "some.token", pbc.Book{Name: "book.pdf"}, pbc.CoverSpec{})
+
+	require.ErrorIs(t, err, pbc.ErrNoCover)
+	require.ErrorIs(t, err, pbc.ErrNotFound)
+}
@@ -0,0 +1,304 @@
+package pocketbook_cloud_client
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DecodeIssueKind is the kind of the difference between the response and the expected shape.
+type DecodeIssueKind string
+
+const (
+	// DecodeIssueUnknownField is reported for the field the client does not know.
+	DecodeIssueUnknownField DecodeIssueKind = "unknown_field"
+	// DecodeIssueTypeMismatch is reported for the value of the unexpected JSON type, even if it was coerced.
+	DecodeIssueTypeMismatch DecodeIssueKind = "type_mismatch"
+)
+
+// DecodeIssue is the difference between the response and the shape expected by the client.
+type DecodeIssue struct {
+	Operation string
+	// Path is the path of the field in the response, e.g. "items[0].percent".
+	Path string
+	Kind DecodeIssueKind
+	// Expected and Got are JSON types of the type mismatch: string, number, bool, object or array.
+	Expected string
+	Got      string
+}
+
+func (i DecodeIssue) Error() string {
+	if i.Kind == DecodeIssueUnknownField {
+		return fmt.Sprintf("%s: unknown field %s", i.Operation, i.Path)
+	}
+
+	return fmt.Sprintf("%s: field %s: expected %s, got %s", i.Operation, i.Path, i.Expected, i.Got)
+}
+
+// decode unmarshals the response body. Coercible type differences are always tolerated,
+// in the strict mode every difference from the expected shape is reported.
+func (c Client) decode(op string, body []byte, v any) error {
+	return c.decodeAt(op, "", body, v)
+}
+
+// decodeAt is decode of the value found at the path of the response, used to decode it by parts.
+func (c Client) decodeAt(op, path string, body []byte, v any) error {
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("unmarshal response body: %w", err)
+	}
+
+	if c.strict == nil {
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	var raw any
+
+	if err := dec.Decode(&raw); err != nil {
+		return fmt.Errorf("unmarshal response body: %w", err)
+	}
+
+	return checkShape(raw, reflect.TypeOf(v).Elem(), path, func(i DecodeIssue) error {
+		i.Operation = op
+
+		return c.strict(i)
+	})
+}
+
+var (
+	timeType            = reflect.TypeFor[time.Time]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// optionalType is implemented by Optional to expose its value type.
+type optionalType interface {
+	optionalElem() reflect.Type
+}
+
+func (Optional[T]) optionalElem() reflect.Type {
+	return reflect.TypeFor[T]()
+}
+
+// lenientType is implemented by types coercing values of other JSON types.
+type lenientType interface {
+	expectedJSON() string
+}
+
+// checkShape compares the decoded JSON value against the type, reporting differences.
+func checkShape(v any, t reflect.Type, path string, report func(DecodeIssue) error) error {
+	if v == nil {
+		return nil
+	}
+
+	got := jsonKind(v)
+
+	mismatch := func(expected string) error {
+		if got == expected {
+			return nil
+		}
+
+		return report(DecodeIssue{Path: path, Kind: DecodeIssueTypeMismatch, Expected: expected, Got: got})
+	}
+
+	if o, ok := reflect.Zero(t).Interface().(optionalType); ok {
+		return checkShape(v, o.optionalElem(), path, report)
+	}
+
+	if l, ok := reflect.Zero(t).Interface().(lenientType); ok {
+		return mismatch(l.expectedJSON())
+	}
+
+	if t == timeType || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return mismatch("string")
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return mismatch("string")
+	case reflect.Bool:
+		return mismatch("bool")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return mismatch("number")
+	case reflect.Slice:
+		if err := mismatch("array"); err != nil || got != "array" {
+			return err
+		}
+
+		for i, e := range v.([]any) {
+			if err := checkShape(e, t.Elem(), fmt.Sprintf("%s[%d]", path, i), report); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		if err := mismatch("object"); err != nil || got != "object" {
+			return err
+		}
+
+		return checkObject(v.(map[string]any), t, path, report)
+	}
+
+	return nil
+}
+
+func checkObject(obj map[string]any, t reflect.Type, path string, report func(DecodeIssue) error) error {
+	fields := make(map[string]reflect.Type, t.NumField())
+
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		fields[name] = f.Type
+	}
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+
+	slices.Sort(keys)
+
+	for _, k := range keys {
+		p := k
+		if path != "" {
+			p = path + "." + k
+		}
+
+		ft, ok := fields[k]
+		if !ok {
+			if err := report(DecodeIssue{Path: p, Kind: DecodeIssueUnknownField}); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err := checkShape(obj[k], ft, p, report); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func jsonKind(v any) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case bool:
+		return "bool"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+
+	return "null"
+}
+
+// lenientString is the string which also accepts numbers and booleans.
+type lenientString string
+
+func (lenientString) expectedJSON() string { return "string" }
+
+func (s lenientString) string() string { return string(s) }
+
+func (s *lenientString) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var v string
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+
+		*s = lenientString(v)
+
+		return nil
+	}
+
+	if string(data) == "null" {
+		return nil
+	}
+
+	*s = lenientString(data)
+
+	return nil
+}
+
+// lenientInt is the integer which also accepts numeric strings and integral floats.
+type lenientInt int64
+
+func (lenientInt) expectedJSON() string { return "number" }
+
+func (n lenientInt) int() int { return int(n) }
+
+func (n lenientInt) int64() int64 { return int64(n) }
+
+func (n *lenientInt) UnmarshalJSON(data []byte) error {
+	s := string(data)
+
+	switch {
+	case s == "null":
+		return nil
+	case len(s) > 0 && s[0] == '"':
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+
+		if s = strings.TrimSpace(s); s == "" {
+			*n = 0
+
+			return nil
+		}
+	}
+
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		*n = lenientInt(i)
+
+		return nil
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f != math.Trunc(f) {
+		return fmt.Errorf("can not coerce %s to integer", data)
+	}
+
+	*n = lenientInt(f)
+
+	return nil
+}
+
+// mapOptional converts the value of the Optional keeping it missing or null.
+func mapOptional[T, U any](o Optional[T], f func(T) U) Optional[U] {
+	if v, ok := o.Get(); ok {
+		return Some(f(v))
+	}
+
+	if o.IsNull() {
+		return Null[U]()
+	}
+
+	return Optional[U]{}
+}
@@ -0,0 +1,107 @@
+package pocketbook_cloud_client_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	pbc "github.com/micronull/pocketbook-cloud-client"
+	"github.com/micronull/pocketbook-cloud-client/mocks"
+)
+
+const driftedBooks = `{"total":"1","items":[{"id":76220203,"percent":4,"new_field":true,"position":{"page":12,"offs":"0"}}]}`
+
+func TestClient_Books_Lenient(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+
+	httpMock.EXPECT().
+		Do(gomock.Any()).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(driftedBooks))}, nil)
+
+	books, err := client.Books(context.Background(), "some.token", 1, 0)
+	require.NoError(t, err)
+	require.Len(t, books.Books, 1)
+
+	assert.Equal(t, 1, books.Total)
+	assert.Equal(t, "76220203", books.Books[0].ID)
+	assert.Equal(t, 4, books.Books[0].ReadPercent)
+	assert.Equal(t, pbc.Some("12"), books.Books[0].Position.Page)
+	assert.Equal(t, pbc.Some(0), books.Books[0].Position.Offs)
+}
+
+func TestClient_Books_Strict(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+
+	var issues []pbc.DecodeIssue
+
+	client := pbc.New(pbc.WithHTTPClient(httpMock), pbc.WithStrictDecoding(func(i pbc.DecodeIssue) error {
+		issues = append(issues, i)
+
+		return nil
+	}))
+
+	gomock.InOrder(
+		httpMock.EXPECT().
+			Do(gomock.Any()).
+			Return(&http.Response{StatusCode: http.StatusOK, Body: must(testdata.Open("testdata/books.json"))}, nil),
+		httpMock.EXPECT().
+			Do(gomock.Any()).
+			Return(&http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(driftedBooks))}, nil),
+	)
+
+	_, err := client.Books(context.Background(), "some.token", 2, 0)
+	require.NoError(t, err)
+	require.Empty(t, issues, "fixture matches the expected shape")
+
+	books, err := client.Books(context.Background(), "some.token", 1, 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, "76220203", books.Books[0].ID, "warnings do not stop decoding")
+
+	expected := []pbc.DecodeIssue{
+		{Operation: pbc.OperationBooks, Path: "items[0].id", Kind: pbc.DecodeIssueTypeMismatch, Expected: "string", Got: "number"},
+		{Operation: pbc.OperationBooks, Path: "items[0].new_field", Kind: pbc.DecodeIssueUnknownField},
+		{Operation: pbc.OperationBooks, Path: "items[0].percent", Kind: pbc.DecodeIssueTypeMismatch, Expected: "string", Got: "number"},
+		{Operation: pbc.OperationBooks, Path: "items[0].position.offs", Kind: pbc.DecodeIssueTypeMismatch, Expected: "number", Got: "string"},
+		{Operation: pbc.OperationBooks, Path: "items[0].position.page", Kind: pbc.DecodeIssueTypeMismatch, Expected: "string", Got: "number"},
+		{Operation: pbc.OperationBooks, Path: "total", Kind: pbc.DecodeIssueTypeMismatch, Expected: "number", Got: "string"},
+	}
+
+	assert.Equal(t, expected, issues)
+}
+
+func TestClient_Providers_Strict_Error(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock), pbc.WithStrictDecoding(nil))
+
+	httpMock.EXPECT().
+		Do(gomock.Any()).
+		Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"providers":[{"alias":"some_provider","shop_id":1}]}`)),
+		}, nil)
+
+	_, err := client.Providers(context.Background(), "some.user.name")
+
+	var issue pbc.DecodeIssue
+	require.ErrorAs(t, err, &issue)
+
+	assert.Equal(t, "providers[0].shop_id", issue.Path)
+	require.ErrorContains(t, err, "Providers: field providers[0].shop_id: expected string, got number")
+}
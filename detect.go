@@ -0,0 +1,82 @@
+package pocketbook_cloud_client
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+)
+
+// DetectHeadSize is how many leading bytes of the file DetectFormat needs.
+const DetectHeadSize = 512
+
+var formatMimeTypes = map[Format]MimeType{
+	FormatEPUB: MimeTypeEPUB,
+	FormatPDF:  MimeTypePDF,
+	FormatFB2:  MimeTypeFB2,
+	FormatMOBI: MimeTypeMOBI,
+	FormatDJVU: MimeTypeDJVU,
+	FormatCBZ:  MimeTypeCBZ,
+	FormatTXT:  MimeTypeTXT,
+	FormatMP3:  MimeTypeMP3,
+	FormatM4B:  MimeTypeM4B,
+}
+
+// MimeType returns the media type of the format, empty for the unknown format.
+func (f Format) MimeType() MimeType {
+	return formatMimeTypes[f]
+}
+
+// DetectFormat detects the format of the book by the magic bytes of its head,
+// falling back to the extension of the name. It returns the empty format when both are unknown.
+func DetectFormat(name string, head []byte) Format {
+	if f := detectMagic(head, name); f != "" {
+		return f
+	}
+
+	f := Format(strings.TrimPrefix(strings.ToLower(filepath.Ext(name)), "."))
+	if !f.Known() {
+		return ""
+	}
+
+	return f
+}
+
+func detectMagic(head []byte, name string) Format {
+	switch {
+	case bytes.HasPrefix(head, []byte("%PDF-")):
+		return FormatPDF
+	case bytes.HasPrefix(head, []byte("AT&TFORM")) && len(head) >= 16 &&
+		(string(head[12:16]) == "DJVU" || string(head[12:16]) == "DJVM"):
+		return FormatDJVU
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")):
+		// EPUB starts with the stored "mimetype" entry, other zip archives are comics.
+		if len(head) >= 58 && string(head[30:58]) == "mimetypeapplication/epub+zip" {
+			return FormatEPUB
+		}
+
+		if strings.EqualFold(filepath.Ext(name), ".epub") {
+			return FormatEPUB
+		}
+
+		return FormatCBZ
+	case len(head) >= 68 && string(head[60:68]) == "BOOKMOBI":
+		return FormatMOBI
+	case len(head) >= 12 && string(head[4:8]) == "ftyp" &&
+		(string(head[8:12]) == "M4B " || string(head[8:12]) == "M4A "):
+		return FormatM4B
+	case bytes.HasPrefix(head, []byte("ID3")), mpegFrame(head):
+		return FormatMP3
+	case bytes.Contains(head, []byte("<FictionBook")):
+		return FormatFB2
+	}
+
+	return ""
+}
+
+// mpegFrame reports whether the head is the MPEG audio Layer III frame header.
+// The frame sync alone also matches the UTF-16LE byte order mark FF FE, which is Layer I.
+func mpegFrame(head []byte) bool {
+	return len(head) >= 2 && head[0] == 0xFF && head[1]&0xE0 == 0xE0 &&
+		head[1]&0x18 != 0x08 && // reserved version
+		head[1]&0x06 == 0x02 // layer III
+}
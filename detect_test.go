@@ -0,0 +1,49 @@
+package pocketbook_cloud_client_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	pbc "github.com/micronull/pocketbook-cloud-client"
+)
+
+func TestDetectFormat(t *testing.T) {
+	t.Parallel()
+
+	zip := "PK\x03\x04" + strings.Repeat("\x00", 26)
+
+	tests := []struct {
+		name     string
+		fileName string
+		head     string
+		want     pbc.Format
+	}{
+		{name: "epub", fileName: "book", head: zip + "mimetypeapplication/epub+zip", want: pbc.FormatEPUB},
+		{name: "epub by extension", fileName: "book.EPUB", head: zip + "META-INF/container.xml", want: pbc.FormatEPUB},
+		{name: "cbz", fileName: "comic", head: zip + "page01.jpg", want: pbc.FormatCBZ},
+		{name: "pdf", fileName: "book.bin", head: "%PDF-1.7", want: pbc.FormatPDF},
+		{name: "fb2", fileName: "book", head: `<?xml version="1.0"?><FictionBook xmlns="">`, want: pbc.FormatFB2},
+		{name: "mobi", fileName: "book", head: strings.Repeat("\x00", 60) + "BOOKMOBI", want: pbc.FormatMOBI},
+		{name: "djvu", fileName: "book", head: "AT&TFORM\x00\x00\x00\x00DJVM", want: pbc.FormatDJVU},
+		{name: "mp3 id3", fileName: "track", head: "ID3\x04\x00", want: pbc.FormatMP3},
+		{name: "mp3 frame", fileName: "track", head: "\xff\xfb\x90\x00", want: pbc.FormatMP3},
+		{name: "m4b", fileName: "audiobook", head: "\x00\x00\x00\x20ftypM4B \x00\x00\x02\x00", want: pbc.FormatM4B},
+		{name: "utf-16 txt", fileName: "notes.txt", head: "\xff\xfeH\x00i\x00", want: pbc.FormatTXT},
+		{name: "utf-16 fb2", fileName: "book.fb2", head: "\xff\xfe<\x00?\x00", want: pbc.FormatFB2},
+		{name: "txt by extension", fileName: "notes.txt", head: "plain text", want: pbc.FormatTXT},
+		{name: "unknown", fileName: "notes.docx", head: "plain text", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := pbc.DetectFormat(tt.fileName, []byte(tt.head))
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.want == "", got.MimeType() == "")
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package pocketbook_cloud_client
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DefaultMaxResumes is how many times an interrupted download is resumed.
+const DefaultMaxResumes = 3
+
+// ErrChecksumMismatch is matched by ChecksumError.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// ChecksumError is returned when the downloaded file differs from Book.Bytes or Book.Md5Hash.
+type ChecksumError struct {
+	Name string
+	// ExpectedSize and Size are the size of the book and of the downloaded file.
+	ExpectedSize int64
+	Size         int64
+	// ExpectedMD5 and MD5 are base64 MD5 of the book and of the downloaded file.
+	ExpectedMD5 string
+	MD5         string
+}
+
+func (e *ChecksumError) Error() string {
+	if e.ExpectedSize != e.Size {
+		return fmt.Sprintf("checksum mismatch of %s: expected %d bytes, got %d", e.Name, e.ExpectedSize, e.Size)
+	}
+
+	return fmt.Sprintf("checksum mismatch of %s: expected md5 %s, got %s", e.Name, e.ExpectedMD5, e.MD5)
+}
+
+// Is reports whether the target is ErrChecksumMismatch.
+func (e *ChecksumError) Is(target error) bool {
+	return target == ErrChecksumMismatch
+}
+
+type downloadConfig struct {
+	progress   func(written, total int64)
+	maxResumes int
+}
+
+type DownloadOption func(*downloadConfig)
+
+// WithProgress calls fn after every chunk written with the number of bytes written so far
+// and the size of the book, 0 when it is unknown.
+func WithProgress(fn func(written, total int64)) DownloadOption {
+	return func(d *downloadConfig) {
+		d.progress = fn
+	}
+}
+
+// WithMaxResumes sets how many times an interrupted transfer is resumed with a Range request.
+// Zero disables resuming.
+func WithMaxResumes(n int) DownloadOption {
+	return func(d *downloadConfig) {
+		d.maxResumes = n
+	}
+}
+
+// Download writes the file of the book to w.
+// The transfer interrupted by a network error is resumed from the received offset.
+// The result is checked against Book.Bytes and Book.Md5Hash, the mismatch is returned as *ChecksumError.
+func (c Client) Download(ctx context.Context, token string, book Book, w io.Writer, opts ...DownloadOption) error {
+	h := md5.New()
+
+	if err := c.download(ctx, token, book, w, h, 0, opts); err != nil {
+		return fmt.Errorf("download %s: %w", book.Name, err)
+	}
+
+	return nil
+}
+
+// DownloadToFile downloads the file of the book to the path.
+// The data is written to the path with the ".part" suffix, renamed to the path once verified.
+// The partial file left by a previous call is resumed, the file failed the check is removed.
+func (c Client) DownloadToFile(ctx context.Context, token string, book Book, path string, opts ...DownloadOption) error {
+	if err := c.downloadToFile(ctx, token, book, path, opts); err != nil {
+		return fmt.Errorf("download %s: %w", book.Name, err)
+	}
+
+	return nil
+}
+
+func (c Client) downloadToFile(ctx context.Context, token string, book Book, path string, opts []DownloadOption) error {
+	part := path + ".part"
+
+	f, err := os.OpenFile(part, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("open file: %w", err)
+	}
+
+	defer func() { _ = f.Close() }()
+
+	h := md5.New()
+
+	offset, err := io.Copy(h, f)
+	if err != nil {
+		return fmt.Errorf("read partial file: %w", err)
+	}
+
+	if book.Bytes > 0 && offset > int64(book.Bytes) {
+		if err = f.Truncate(0); err != nil {
+			return fmt.Errorf("truncate file: %w", err)
+		}
+
+		if _, err = f.Seek(0, io.SeekStart); err != nil {
+			return fmt.Errorf("seek file: %w", err)
+		}
+
+		h.Reset()
+
+		offset = 0
+	}
+
+	err = c.download(ctx, token, book, f, h, offset, opts)
+	if err != nil {
+		if errors.Is(err, ErrChecksumMismatch) {
+			_ = f.Close()
+			_ = os.Remove(part)
+		}
+
+		return err
+	}
+
+	if err = f.Sync(); err != nil {
+		return fmt.Errorf("sync file: %w", err)
+	}
+
+	if err = f.Close(); err != nil {
+		return fmt.Errorf("close file: %w", err)
+	}
+
+	if err = os.Rename(part, path); err != nil {
+		return fmt.Errorf("rename file: %w", err)
+	}
+
+	return nil
+}
+
+// download writes the file from the offset to w, h has already hashed the data before the offset.
+func (c Client) download(ctx context.Context, token string, book Book, w io.Writer, h hash.Hash, offset int64, opts []DownloadOption) error {
+	cfg := downloadConfig{maxResumes: DefaultMaxResumes}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	total := int64(book.Bytes)
+	written := offset
+
+	for resumes := 0; total == 0 || written < total; resumes++ {
+		rsp, err := c.do(OperationDownload, c.downloadRequest(ctx, token, book, written))
+		if err != nil {
+			return err
+		}
+
+		n, resumable, err := copyBody(rsp, written, io.MultiWriter(w, h), func(n int64) {
+			if cfg.progress != nil {
+				cfg.progress(written+n, total)
+			}
+		})
+
+		_ = rsp.Body.Close()
+
+		written += n
+
+		if err == nil {
+			break
+		}
+
+		if !resumable || resumes >= cfg.maxResumes || ctx.Err() != nil {
+			return err
+		}
+	}
+
+	return verifyDownload(book, written, h)
+}
+
+// downloadRequest builds the request to the file of the book addressed by Book.Path like Upload and DeleteBook,
+// by Book.Name in the root when the path is empty.
+// Book.Link is not requested, it carries the access token in the query.
+func (c Client) downloadRequest(ctx context.Context, token string, book Book, offset int64) *http.Request {
+	name := book.Path
+	if name == "" {
+		name = book.Name
+	}
+
+	u := c.url(files).JoinPath(libraryPath(name))
+
+	if book.FastHash != "" {
+		u.RawQuery = url.Values{"fast_hash": []string{book.FastHash}}.Encode()
+	}
+
+	req := &http.Request{
+		Method: http.MethodGet,
+		URL:    u,
+		Body:   http.NoBody,
+		Header: http.Header{"Authorization": []string{string(TokenTypeBearer) + " " + token}},
+	}
+
+	if offset > 0 {
+		req.Header.Set("Range", "bytes="+strconv.FormatInt(offset, 10)+"-")
+	}
+
+	return req.WithContext(ctx)
+}
+
+// copyBody copies the response body starting at the offset to w.
+// Only the transfer interrupted by the read error is resumable.
+func copyBody(rsp *http.Response, offset int64, w io.Writer, progress func(int64)) (n int64, resumable bool, err error) {
+	if offset > 0 {
+		switch rsp.StatusCode {
+		case http.StatusPartialContent:
+			if start, ok := contentRangeStart(rsp.Header.Get("Content-Range")); !ok || start != offset {
+				return 0, false, fmt.Errorf("unexpected content range %q", rsp.Header.Get("Content-Range"))
+			}
+		default:
+			// The server ignored the range and sent the whole file.
+			if _, err = io.CopyN(io.Discard, rsp.Body, offset); err != nil {
+				return 0, true, fmt.Errorf("read response body: %w", err)
+			}
+		}
+	}
+
+	buf := make([]byte, 32<<10)
+
+	for {
+		m, rerr := rsp.Body.Read(buf)
+		if m > 0 {
+			if _, err = w.Write(buf[:m]); err != nil {
+				return n, false, fmt.Errorf("write: %w", err)
+			}
+
+			n += int64(m)
+			progress(n)
+		}
+
+		if errors.Is(rerr, io.EOF) {
+			return n, false, nil
+		}
+
+		if rerr != nil {
+			return n, true, fmt.Errorf("read response body: %w", rerr)
+		}
+	}
+}
+
+// contentRangeStart returns the first byte position of the "bytes first-last/size" header.
+func contentRangeStart(v string) (int64, bool) {
+	v, ok := strings.CutPrefix(v, "bytes ")
+	if !ok {
+		return 0, false
+	}
+
+	first, _, ok := strings.Cut(v, "-")
+	if !ok {
+		return 0, false
+	}
+
+	start, err := strconv.ParseInt(first, 10, 64)
+
+	return start, err == nil
+}
+
+func verifyDownload(book Book, size int64, h hash.Hash) error {
+	sum := base64.StdEncoding.EncodeToString(h.Sum(nil))
+
+	if (book.Bytes > 0 && size != int64(book.Bytes)) || (book.Md5Hash != "" && sum != book.Md5Hash) {
+		return &ChecksumError{
+			Name:         book.Name,
+			ExpectedSize: int64(book.Bytes),
+			Size:         size,
+			ExpectedMD5:  book.Md5Hash,
+			MD5:          sum,
+		}
+	}
+
+	return nil
+}
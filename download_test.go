@@ -0,0 +1,255 @@
+package pocketbook_cloud_client_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	pbc "github.com/micronull/pocketbook-cloud-client"
+	"github.com/micronull/pocketbook-cloud-client/mocks"
+)
+
+func downloadFixture() ([]byte, pbc.Book) {
+	content := bytes.Repeat([]byte("Война и мир. "), 10_000)
+	sum := md5.Sum(content)
+
+	return content, pbc.Book{
+		Name:     "voina-i-mir.epub",
+		Bytes:    len(content),
+		FastHash: "5c624ec0db399a8f1b99eddabf1e22c1",
+		Md5Hash:  base64.StdEncoding.EncodeToString(sum[:]),
+	}
+}
+
+func partialResponse(content []byte, offset int) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusPartialContent,
+		Header: http.Header{"Content-Range": []string{
+			"bytes " + strconv.Itoa(offset) + "-" + strconv.Itoa(len(content)-1) + "/" + strconv.Itoa(len(content)),
+		}},
+		Body: io.NopCloser(bytes.NewReader(content[offset:])),
+	}
+}
+
+func TestClient_Download(t *testing.T) {
+	t.Parallel()
+
+	content, book := downloadFixture()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+
+	httpMock.EXPECT().
+		Do(mock.MatchedBy(func(req *http.Request) bool {
+			return isAllTrue(
+				assert.Equal(t, http.MethodGet, req.Method),
+				assert.Equal(t, "/api/v1.0/files/voina-i-mir.epub", req.URL.Path),
+				assert.Equal(t, "fast_hash=5c624ec0db399a8f1b99eddabf1e22c1", req.URL.RawQuery),
+				assert.Equal(t, "Bearer some.token", req.Header.Get("Authorization")),
+				assert.Empty(t, req.Header.Get("Range")),
+			)
+		})).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(content))}, nil)
+
+	var (
+		buf  bytes.Buffer
+		last [2]int64
+	)
+
+	err := client.Download(context.Background(), "some.token", book, &buf, pbc.WithProgress(func(written, total int64) {
+		last = [2]int64{written, total}
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, content, buf.Bytes())
+	assert.Equal(t, [2]int64{int64(len(content)), int64(len(content))}, last)
+}
+
+func TestClient_Download_Folder(t *testing.T) {
+	t.Parallel()
+
+	content, _ := downloadFixture()
+
+	fs, client := newFileServer(t)
+	fs.put("/classic/voina-i-mir.epub", content, string(pbc.MimeTypeEPUB), "")
+	fs.put("/voina-i-mir.epub", epubFile(), string(pbc.MimeTypeEPUB), "")
+
+	var buf bytes.Buffer
+
+	require.NoError(t, client.Download(context.Background(), "some.token", fs.book(t, "/classic/voina-i-mir.epub"), &buf))
+	assert.Equal(t, content, buf.Bytes())
+}
+
+func TestClient_Download_Resume(t *testing.T) {
+	t.Parallel()
+
+	content, book := downloadFixture()
+	half := len(content) / 2
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+
+	gomock.InOrder(
+		httpMock.EXPECT().
+			Do(gomock.Any()).
+			Return(&http.Response{
+				StatusCode: http.StatusOK,
+				Body: io.NopCloser(io.MultiReader(
+					bytes.NewReader(content[:half]),
+					iotest.ErrReader(io.ErrUnexpectedEOF),
+				)),
+			}, nil),
+		httpMock.EXPECT().
+			Do(mock.MatchedBy(func(req *http.Request) bool {
+				return assert.Equal(t, "bytes="+strconv.Itoa(half)+"-", req.Header.Get("Range"))
+			})).
+			Return(partialResponse(content, half), nil),
+	)
+
+	var buf bytes.Buffer
+
+	require.NoError(t, client.Download(context.Background(), "some.token", book, &buf))
+	assert.Equal(t, content, buf.Bytes())
+}
+
+func TestClient_Download_RangeIgnored(t *testing.T) {
+	t.Parallel()
+
+	content, book := downloadFixture()
+	half := len(content) / 2
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+
+	gomock.InOrder(
+		httpMock.EXPECT().
+			Do(gomock.Any()).
+			Return(&http.Response{
+				StatusCode: http.StatusOK,
+				Body: io.NopCloser(io.MultiReader(
+					bytes.NewReader(content[:half]),
+					iotest.ErrReader(io.ErrUnexpectedEOF),
+				)),
+			}, nil),
+		httpMock.EXPECT().
+			Do(gomock.Any()).
+			Return(&http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(content))}, nil),
+	)
+
+	var buf bytes.Buffer
+
+	require.NoError(t, client.Download(context.Background(), "some.token", book, &buf))
+	assert.Equal(t, content, buf.Bytes())
+}
+
+func TestClient_Download_NoResume(t *testing.T) {
+	t.Parallel()
+
+	content, book := downloadFixture()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+
+	httpMock.EXPECT().
+		Do(gomock.Any()).
+		Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(io.MultiReader(
+				bytes.NewReader(content[:10]),
+				iotest.ErrReader(io.ErrUnexpectedEOF),
+			)),
+		}, nil)
+
+	err := client.Download(context.Background(), "some.token", book, io.Discard, pbc.WithMaxResumes(0))
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestClient_Download_ChecksumMismatch(t *testing.T) {
+	t.Parallel()
+
+	content, book := downloadFixture()
+	content = bytes.Clone(content)
+	content[0] = 'X'
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+
+	httpMock.EXPECT().
+		Do(gomock.Any()).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(content))}, nil)
+
+	err := client.Download(context.Background(), "some.token", book, io.Discard)
+	require.ErrorIs(t, err, pbc.ErrChecksumMismatch)
+
+	var cerr *pbc.ChecksumError
+
+	require.True(t, errors.As(err, &cerr))
+	assert.Equal(t, book.Md5Hash, cerr.ExpectedMD5)
+	assert.NotEqual(t, cerr.ExpectedMD5, cerr.MD5)
+	assert.Equal(t, cerr.ExpectedSize, cerr.Size)
+}
+
+func TestClient_DownloadToFile(t *testing.T) {
+	t.Parallel()
+
+	content, book := downloadFixture()
+	half := len(content) / 2
+	path := filepath.Join(t.TempDir(), book.Name)
+
+	require.NoError(t, os.WriteFile(path+".part", content[:half], 0o644))
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+
+	httpMock.EXPECT().
+		Do(mock.MatchedBy(func(req *http.Request) bool {
+			return assert.Equal(t, "bytes="+strconv.Itoa(half)+"-", req.Header.Get("Range"))
+		})).
+		Return(partialResponse(content, half), nil)
+
+	require.NoError(t, client.DownloadToFile(context.Background(), "some.token", book, path))
+
+	assert.Equal(t, content, must(os.ReadFile(path)))
+	assert.NoFileExists(t, path+".part")
+}
+
+func TestClient_DownloadToFile_ChecksumMismatch(t *testing.T) {
+	t.Parallel()
+
+	_, book := downloadFixture()
+	path := filepath.Join(t.TempDir(), book.Name)
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+
+	httpMock.EXPECT().
+		Do(gomock.Any()).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader([]byte("broken")))}, nil)
+
+	err := client.DownloadToFile(context.Background(), "some.token", book, path)
+	require.ErrorIs(t, err, pbc.ErrChecksumMismatch)
+
+	assert.NoFileExists(t, path)
+	assert.NoFileExists(t, path+".part")
+}
@@ -1,18 +1,166 @@
 package pocketbook_cloud_client
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 )
 
-type httpStatusError struct {
-	code int
+var (
+	// ErrUnauthorized is matched by APIError of 401 Unauthorized.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrNotFound is matched by APIError of 404 Not Found.
+	ErrNotFound = errors.New("not found")
+	// ErrRateLimited is matched by APIError of 429 Too Many Requests.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrServer is matched by APIError of 5xx status codes.
+	ErrServer = errors.New("server error")
+	// ErrConflict is matched by APIError of 409 Conflict and 412 Precondition Failed,
+	// e.g. when the book has been changed since it was listed.
+	ErrConflict = errors.New("conflict")
+)
+
+// ErrNoRenewal is returned when the token has expired and there is neither a refresh token nor a login fallback.
+var ErrNoRenewal = errors.New("token can not be renewed")
+
+// ErrResponseTooLarge is returned when the response body exceeds the maximum size set by WithMaxResponseSize.
+var ErrResponseTooLarge = errors.New("response too large")
+
+// APIError is the unsuccessful response of the API.
+// Use errors.Is with ErrUnauthorized, ErrNotFound, ErrRateLimited, ErrServer and ErrConflict to check the kind of error.
+type APIError struct {
+	// StatusCode is the http status code of the response.
+	StatusCode int
+	// Endpoint is the path of the request relative to the API root, e.g. "books".
+	Endpoint string
+	// RequestID is the request id from the response headers, if present.
+	RequestID string
+	// Payload is the error returned by the server in the response body.
+	Payload ErrorPayload
+}
+
+// ErrorPayload is the error body of the response.
+// Both OAuth style {"error", "error_description"} and {"code", "message"} bodies are decoded.
+type ErrorPayload struct {
+	Code    string
+	Message string
+	// Raw is the undecoded response body.
+	Raw []byte
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "http status code: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+
+	if e.Endpoint != "" {
+		b.WriteString(" endpoint=" + e.Endpoint)
+	}
+
+	if e.RequestID != "" {
+		b.WriteString(" requestID=" + e.RequestID)
+	}
+
+	switch {
+	case e.Payload.Code != "" && e.Payload.Message != "":
+		b.WriteString(": " + e.Payload.Code + ": " + e.Payload.Message)
+	case e.Payload.Message != "":
+		b.WriteString(": " + e.Payload.Message)
+	case e.Payload.Code != "":
+		b.WriteString(": " + e.Payload.Code)
+	}
+
+	return b.String()
+}
+
+// Code returns the http status code of the response.
+func (e *APIError) Code() int {
+	return e.StatusCode
+}
+
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= http.StatusInternalServerError
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict || e.StatusCode == http.StatusPreconditionFailed
+	}
+
+	return false
+}
+
+var requestIDHeaders = []string{"X-Request-Id", "X-Correlation-Id", "X-Amzn-Requestid"}
+
+func newAPIError(endpoint string, rsp *http.Response, body []byte) *APIError {
+	e := &APIError{
+		StatusCode: rsp.StatusCode,
+		Endpoint:   endpoint,
+		Payload:    decodeErrorPayload(body),
+	}
+
+	for _, h := range requestIDHeaders {
+		if id := rsp.Header.Get(h); id != "" {
+			e.RequestID = id
+
+			break
+		}
+	}
+
+	return e
 }
 
-func (e httpStatusError) Error() string {
-	return fmt.Sprintf("http status code: %d %s", e.code, http.StatusText(e.code))
+func decodeErrorPayload(body []byte) ErrorPayload {
+	p := ErrorPayload{Raw: body}
+
+	var data map[string]json.RawMessage
+
+	if json.Unmarshal(body, &data) != nil {
+		return p
+	}
+
+	// {"error": {"code": ..., "message": ...}}
+	if nested, ok := data["error"]; ok && len(nested) > 0 && nested[0] == '{' {
+		var inner map[string]json.RawMessage
+		if json.Unmarshal(nested, &inner) == nil {
+			data = inner
+		}
+	}
+
+	p.Code = firstJSONString(data, "error", "code", "error_code")
+	p.Message = firstJSONString(data, "error_description", "message", "detail")
+
+	return p
 }
 
-func (e httpStatusError) Code() int {
-	return e.code
+// firstJSONString returns the first of keys holding a string or a number.
+func firstJSONString(data map[string]json.RawMessage, keys ...string) string {
+	for _, k := range keys {
+		raw, ok := data[k]
+		if !ok {
+			continue
+		}
+
+		var s string
+		if json.Unmarshal(raw, &s) == nil && s != "" {
+			return s
+		}
+
+		var n json.Number
+		if json.Unmarshal(raw, &n) == nil {
+			if _, err := strconv.ParseFloat(n.String(), 64); err == nil {
+				return n.String()
+			}
+		}
+	}
+
+	return ""
 }
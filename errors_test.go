@@ -0,0 +1,123 @@
+package pocketbook_cloud_client_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	pbc "github.com/micronull/pocketbook-cloud-client"
+	"github.com/micronull/pocketbook-cloud-client/mocks"
+)
+
+func TestAPIError_Is(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		code int
+		want error
+	}{
+		{code: http.StatusUnauthorized, want: pbc.ErrUnauthorized},
+		{code: http.StatusNotFound, want: pbc.ErrNotFound},
+		{code: http.StatusTooManyRequests, want: pbc.ErrRateLimited},
+		{code: http.StatusInternalServerError, want: pbc.ErrServer},
+		{code: http.StatusBadGateway, want: pbc.ErrServer},
+		{code: http.StatusConflict, want: pbc.ErrConflict},
+		{code: http.StatusPreconditionFailed, want: pbc.ErrConflict},
+	}
+
+	sentinels := []error{pbc.ErrUnauthorized, pbc.ErrNotFound, pbc.ErrRateLimited, pbc.ErrServer, pbc.ErrConflict}
+
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.code), func(t *testing.T) {
+			t.Parallel()
+
+			ctrlMock := gomock.NewController(t)
+			httpMock := mocks.NewMockDoer(ctrlMock)
+			client := pbc.New(pbc.WithHTTPClient(httpMock))
+
+			httpMock.EXPECT().
+				Do(gomock.Any()).
+				Return(&http.Response{StatusCode: tt.code, Body: http.NoBody}, nil)
+
+			_, err := client.Books(context.Background(), "some.token", 1, 0)
+
+			for _, s := range sentinels {
+				assert.Equal(t, s == tt.want, errors.Is(err, s), s.Error())
+			}
+		})
+	}
+}
+
+func TestAPIError_Payload(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+	body := &closeTracker{Reader: strings.NewReader(`{"error":"invalid_grant","error_description":"Invalid username and password combination"}`)}
+
+	httpMock.EXPECT().
+		Do(gomock.Any()).
+		Return(&http.Response{
+			StatusCode: http.StatusUnauthorized,
+			Header:     http.Header{"X-Request-Id": []string{"some.request.id"}},
+			Body:       body,
+		}, nil)
+
+	_, err := client.Login(context.Background(), pbc.LoginRequest{Provider: "some_provider"})
+	require.ErrorIs(t, err, pbc.ErrUnauthorized)
+	require.ErrorContains(t, err, "invalid_grant: Invalid username and password combination")
+
+	var apiErr *pbc.APIError
+	require.ErrorAs(t, err, &apiErr)
+
+	assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+	assert.Equal(t, "auth/login/some_provider", apiErr.Endpoint)
+	assert.Equal(t, "some.request.id", apiErr.RequestID)
+	assert.Equal(t, "invalid_grant", apiErr.Payload.Code)
+	assert.Equal(t, "Invalid username and password combination", apiErr.Payload.Message)
+	assert.True(t, body.closed, "response body must be closed")
+}
+
+func TestAPIError_Payload_Nested(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+
+	httpMock.EXPECT().
+		Do(gomock.Any()).
+		Return(&http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       io.NopCloser(strings.NewReader(`{"error":{"code":404,"message":"Not found"}}`)),
+		}, nil)
+
+	_, err := client.Providers(context.Background(), "some.user.name")
+
+	var apiErr *pbc.APIError
+	require.ErrorAs(t, err, &apiErr)
+
+	assert.Equal(t, "auth/login", apiErr.Endpoint)
+	assert.Equal(t, "404", apiErr.Payload.Code)
+	assert.Equal(t, "Not found", apiErr.Payload.Message)
+}
+
+type closeTracker struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeTracker) Close() error {
+	c.closed = true
+
+	return nil
+}
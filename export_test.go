@@ -0,0 +1,4 @@
+package pocketbook_cloud_client
+
+// PBKDF2SHA256 exposes the key derivation to the tests.
+var PBKDF2SHA256 = pbkdf2SHA256
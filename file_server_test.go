@@ -0,0 +1,238 @@
+package pocketbook_cloud_client_test
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	pbc "github.com/micronull/pocketbook-cloud-client"
+)
+
+// fileServer is the stand-in of the library keeping uploaded files in memory.
+type fileServer struct {
+	t        *testing.T
+	failures atomic.Int32
+
+	mu     sync.Mutex
+	nextID int
+	files  map[string]*serverFile
+}
+
+type serverFile struct {
+	id    string
+	body  []byte
+	mime  string
+	mtime string
+}
+
+func newFileServer(t *testing.T, opts ...pbc.Option) (*fileServer, *pbc.Client) {
+	t.Helper()
+
+	fs := &fileServer{t: t, files: map[string]*serverFile{}}
+	srv := httptest.NewServer(fs)
+
+	t.Cleanup(srv.Close)
+
+	u := must(url.Parse(srv.URL)).JoinPath(pbc.DefaultPath)
+
+	opts = append([]pbc.Option{pbc.WithBaseURL(u), pbc.WithHTTPClient(srv.Client()), pbc.WithRetry(testRetryPolicy())}, opts...)
+
+	return fs, pbc.New(opts...)
+}
+
+// put stores the file as if it was uploaded.
+func (s *fileServer) put(name string, body []byte, mime, mtime string) *serverFile {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, ok := s.files[name]
+	if !ok {
+		s.nextID++
+		f = &serverFile{id: strconv.Itoa(76220200 + s.nextID)}
+		s.files[name] = f
+	}
+
+	f.body, f.mime, f.mtime = body, mime, mtime
+
+	return f
+}
+
+func (s *fileServer) body(name string) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if f, ok := s.files[name]; ok {
+		return f.body
+	}
+
+	return nil
+}
+
+// book returns the stored file as it is listed by the library.
+func (s *fileServer) book(t *testing.T, name string) pbc.Book {
+	t.Helper()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, ok := s.files[name]
+	if !ok {
+		t.Fatalf("no file %s", name)
+	}
+
+	return pbc.Book{ID: f.id, Path: name, Name: path.Base(name), FastHash: f.fastHash()}
+}
+
+func (s *fileServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.failures.Load() > 0 {
+		s.failures.Add(-1)
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusServiceUnavailable)
+
+		return
+	}
+
+	if r.Header.Get("Authorization") != "Bearer some.token" {
+		w.WriteHeader(http.StatusUnauthorized)
+
+		return
+	}
+
+	endpoint := strings.TrimPrefix(r.URL.Path, pbc.DefaultPath)
+
+	switch name, isFile := strings.CutPrefix(endpoint, "files"); {
+	case endpoint == "books" && r.Method == http.MethodGet:
+		s.serveBooks(w)
+	case isFile && r.Method == http.MethodPut:
+		body, err := io.ReadAll(r.Body)
+		if !assert.NoError(s.t, err) {
+			return
+		}
+
+		f := s.put(name, body, r.Header.Get("Content-Type"), r.URL.Query().Get("client_mtime"))
+
+		w.WriteHeader(http.StatusCreated)
+
+		_ = json.NewEncoder(w).Encode(f.book(name))
+	case isFile:
+		s.serveFile(w, r, name)
+	case strings.HasPrefix(endpoint, "books/"):
+		s.serveFile(w, r, s.nameByID(strings.TrimPrefix(endpoint, "books/")))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (s *fileServer) nameByID(id string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for name, f := range s.files {
+		if f.id == id {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// serveFile sends, deletes or moves the file guarded by its fast hash.
+func (s *fileServer) serveFile(w http.ResponseWriter, r *http.Request, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, ok := s.files[name]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	if r.URL.Query().Get("fast_hash") != f.fastHash() {
+		w.WriteHeader(http.StatusPreconditionFailed)
+
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		_, _ = w.Write(f.body)
+	case http.MethodDelete:
+		delete(s.files, name)
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodPatch:
+		var data struct {
+			Path string `json:"path"`
+		}
+
+		if !assert.NoError(s.t, json.NewDecoder(r.Body).Decode(&data)) {
+			return
+		}
+
+		if _, taken := s.files[data.Path]; taken {
+			w.WriteHeader(http.StatusConflict)
+
+			return
+		}
+
+		delete(s.files, name)
+		s.files[data.Path] = f
+
+		_ = json.NewEncoder(w).Encode(f.book(data.Path))
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *fileServer) serveBooks(w http.ResponseWriter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := make([]map[string]any, 0, len(s.files))
+
+	for _, name := range slices.Sorted(maps.Keys(s.files)) {
+		items = append(items, s.files[name].book(name))
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]any{"total": len(items), "items": items})
+}
+
+func (f *serverFile) fastHash() string {
+	return must(pbc.FastHash(bytes.NewReader(f.body)))
+}
+
+func (f *serverFile) book(name string) map[string]any {
+	sum := md5.Sum(f.body)
+
+	book := map[string]any{
+		"id":        f.id,
+		"path":      name,
+		"name":      path.Base(name),
+		"bytes":     len(f.body),
+		"mime_type": f.mime,
+		"format":    strings.TrimPrefix(path.Ext(name), "."),
+		"md5_hash":  base64.StdEncoding.EncodeToString(sum[:]),
+		"fast_hash": f.fastHash(),
+	}
+
+	if f.mtime != "" {
+		book["client_mtime"] = f.mtime
+	}
+
+	return book
+}
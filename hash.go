@@ -0,0 +1,55 @@
+package pocketbook_cloud_client
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Md5Hash returns the base64 MD5 of the content, comparable with Book.Md5Hash.
+func Md5Hash(r io.Reader) (string, error) {
+	h := md5.New()
+
+	if _, err := io.Copy(h, r); err != nil {
+		return "", fmt.Errorf("md5 hash: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(h.Sum(nil)), nil
+}
+
+// FastHash returns the sampled hash of the content, comparable with Book.FastHash.
+// It is the hex MD5 of 1 KiB chunks at offsets 0 and 1024·4^i for i from 0 to 10,
+// so it reads at most 12 KiB of any file.
+func FastHash(r io.ReaderAt) (string, error) {
+	const step = 1024
+
+	h := md5.New()
+	buf := make([]byte, step)
+
+	for i := -1; i <= 10; i++ {
+		offset := int64(0)
+		if i >= 0 {
+			offset = step << (2 * i)
+		}
+
+		n, err := r.ReadAt(buf, offset)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", fmt.Errorf("fast hash: %w", err)
+		}
+
+		if n == 0 {
+			break
+		}
+
+		h.Write(buf[:n])
+
+		if n < step {
+			break
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
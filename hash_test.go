@@ -0,0 +1,99 @@
+package pocketbook_cloud_client_test
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pbc "github.com/micronull/pocketbook-cloud-client"
+)
+
+func TestMd5Hash(t *testing.T) {
+	t.Parallel()
+
+	content := must(testdata.ReadFile("testdata/books.json"))
+	sum := md5.Sum(content)
+
+	got, err := pbc.Md5Hash(bytes.NewReader(content))
+	require.NoError(t, err)
+
+	assert.Equal(t, base64.StdEncoding.EncodeToString(sum[:]), got)
+}
+
+func TestFastHash(t *testing.T) {
+	t.Parallel()
+
+	content := make([]byte, 20_000)
+	for i := range content {
+		content[i] = byte(i * 7)
+	}
+
+	tests := []struct {
+		name    string
+		content []byte
+		samples []byte
+	}{
+		{name: "empty", content: nil, samples: nil},
+		{name: "smaller than chunk", content: content[:100], samples: content[:100]},
+		{
+			name:    "sampled",
+			content: content,
+			samples: bytes.Join([][]byte{
+				content[0:1024],
+				content[1024:2048],
+				content[4096:5120],
+				content[16384:17408],
+			}, nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sum := md5.Sum(tt.samples)
+
+			got, err := pbc.FastHash(bytes.NewReader(tt.content))
+			require.NoError(t, err)
+
+			assert.Equal(t, hex.EncodeToString(sum[:]), got)
+		})
+	}
+}
+
+// TestFastHash_Vectors pins FastHash to fixed values of its sampling scheme, so that a change of it is noticed.
+// The values follow the same scheme, they do not prove compatibility with Book.FastHash of the cloud.
+func TestFastHash_Vectors(t *testing.T) {
+	t.Parallel()
+
+	content := make([]byte, 5_000_000)
+	for i := range content {
+		content[i] = byte(i * 7)
+	}
+
+	tests := []struct {
+		name    string
+		content []byte
+		want    string
+	}{
+		{name: "empty", content: nil, want: "d41d8cd98f00b204e9800998ecf8427e"},
+		{name: "5 MB", content: content, want: "7007dd9c0613a71b583ef7ab5ebec811"},
+		{name: "partial last chunk", content: content[:4_194_804], want: "784387b6eab6881342e5b1518ce001c4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := pbc.FastHash(bytes.NewReader(tt.content))
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package pocketbook_cloud_client
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+const redacted = "REDACTED"
+
+// sensitiveParams are redacted from query strings, forms and JSON bodies in logs.
+var sensitiveParams = map[string]bool{
+	"password":      true,
+	"client_secret": true,
+	"access_token":  true,
+	"refresh_token": true,
+}
+
+var (
+	// The closing quote is optional, the value may be cut at the end of the logged head of the body.
+	sensitiveJSON  = regexp.MustCompile(`("(?:password|client_secret|access_token|refresh_token)"\s*:\s*)"(?:[^"\\]|\\.?)*"?`)
+	sensitiveQuery = regexp.MustCompile(`((?:password|client_secret|access_token|refresh_token)=)[^&"\s]*`)
+)
+
+// logging logs every attempt of requests with secrets redacted.
+// Bodies are dumped when the logger is enabled for the debug level.
+func (c Client) logging(logger *slog.Logger) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			ctx := req.Context()
+			debug := logger.Enabled(ctx, slog.LevelDebug)
+			attrs := []slog.Attr{
+				slog.String("method", req.Method),
+				slog.String("endpoint", c.endpoint(req.URL)),
+			}
+
+			if debug {
+				reqAttrs := slices.Concat(attrs, []slog.Attr{
+					slog.String("url", redactURL(req.URL)),
+					slog.Any("headers", redactHeader(req.Header)),
+				})
+
+				// Uploaded files are not dumped, GetBody rewinds the file of the caller in place.
+				if contentType := req.Header.Get("Content-Type"); builtBody(contentType) {
+					if body, ok := requestBody(req); ok {
+						reqAttrs = append(reqAttrs, slog.String("body", logBody(contentType, body)))
+					}
+				}
+
+				logger.LogAttrs(ctx, slog.LevelDebug, "pocketbook request", reqAttrs...)
+			}
+
+			start := time.Now()
+
+			rsp, err := next.Do(req)
+
+			attrs = append(attrs, slog.Duration("duration", time.Since(start)))
+
+			if err != nil {
+				logger.LogAttrs(ctx, slog.LevelError, "pocketbook request failed", append(attrs, slog.String("error", redactText(err.Error())))...)
+
+				return rsp, err
+			}
+
+			attrs = append(attrs, slog.Int("status", rsp.StatusCode))
+
+			// The size of chunked or compressed responses is unknown until they are read by the caller.
+			if rsp.ContentLength >= 0 {
+				attrs = append(attrs, slog.Int64("size", rsp.ContentLength))
+			}
+
+			if contentType := rsp.Header.Get("Content-Type"); debug && rsp.Body != nil && (contentType == "" || textual(contentType)) {
+				// Only the head of the body is read, the rest is left streaming to the caller.
+				// The body without the content type is sniffed.
+				head, rerr := io.ReadAll(io.LimitReader(rsp.Body, maxLogBodySize))
+				if rerr != nil {
+					_ = rsp.Body.Close()
+
+					return nil, rerr
+				}
+
+				rsp.Body = struct {
+					io.Reader
+					io.Closer
+				}{io.MultiReader(bytes.NewReader(head), rsp.Body), rsp.Body}
+
+				if textual(contentType) || textual(http.DetectContentType(head)) {
+					attrs = append(attrs, slog.String("body", logBody(contentType, head)))
+				}
+			}
+
+			level := slog.LevelInfo
+			if rsp.StatusCode >= http.StatusBadRequest {
+				level = slog.LevelWarn
+			}
+
+			logger.LogAttrs(ctx, level, "pocketbook response", attrs...)
+
+			return rsp, nil
+		})
+	}
+}
+
+// maxLogBodySize limits the head of bodies dumped to the log.
+const maxLogBodySize = maxErrorBodySize
+
+// textual reports whether the body of the content type is text worth logging, e.g. JSON or a form.
+func textual(contentType string) bool {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return strings.HasPrefix(mt, "text/") || mt == "application/json" || strings.HasSuffix(mt, "+json") ||
+		mt == "application/x-www-form-urlencoded"
+}
+
+// builtBody reports whether the request body of the content type is the form or JSON the client builds in memory.
+func builtBody(contentType string) bool {
+	mt, _, err := mime.ParseMediaType(contentType)
+
+	return err == nil && (mt == "application/json" || mt == "application/x-www-form-urlencoded")
+}
+
+// logBody returns the redacted body, marking it when it is longer than maxLogBodySize.
+func logBody(contentType string, body []byte) string {
+	if len(body) < maxLogBodySize {
+		return redactBody(contentType, body)
+	}
+
+	return redactBody(contentType, body[:maxLogBodySize]) + "...(truncated)"
+}
+
+// requestBody returns the head of the request body without consuming it.
+func requestBody(req *http.Request) ([]byte, bool) {
+	if req.GetBody == nil {
+		return nil, false
+	}
+
+	rc, err := req.GetBody()
+	if err != nil {
+		return nil, false
+	}
+
+	defer func() { _ = rc.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(rc, maxLogBodySize))
+	if err != nil {
+		return nil, false
+	}
+
+	return body, true
+}
+
+func redactURL(u *url.URL) string {
+	r := *u
+	r.RawQuery = redactValues(u.Query()).Encode()
+
+	if r.User != nil {
+		r.User = url.User(r.User.Username())
+	}
+
+	return r.String()
+}
+
+func redactValues(q url.Values) url.Values {
+	for k := range q {
+		if sensitiveParams[strings.ToLower(k)] {
+			q[k] = []string{redacted}
+		}
+	}
+
+	return q
+}
+
+func redactHeader(h http.Header) http.Header {
+	h = h.Clone()
+
+	if v := h.Get("Authorization"); v != "" {
+		scheme, _, _ := strings.Cut(v, " ")
+		h.Set("Authorization", scheme+" "+redacted)
+	}
+
+	return h
+}
+
+func redactBody(contentType string, body []byte) string {
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		if q, err := url.ParseQuery(string(body)); err == nil {
+			return redactValues(q).Encode()
+		}
+	}
+
+	return redactText(string(body))
+}
+
+// redactText redacts secrets of JSON fields and query parameters inside any text.
+func redactText(s string) string {
+	s = sensitiveJSON.ReplaceAllString(s, `$1"`+redacted+`"`)
+
+	return sensitiveQuery.ReplaceAllString(s, "${1}"+redacted)
+}
@@ -0,0 +1,199 @@
+package pocketbook_cloud_client_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	pbc "github.com/micronull/pocketbook-cloud-client"
+	"github.com/micronull/pocketbook-cloud-client/mocks"
+)
+
+func TestClient_WithLogger_Debug(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	out := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(out, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	client := pbc.New(
+		pbc.WithHTTPClient(httpMock),
+		pbc.WithClientID("some.client.id"),
+		pbc.WithClientSecret("some.client.secret"),
+		pbc.WithLogger(logger),
+	)
+
+	gomock.InOrder(
+		httpMock.EXPECT().
+			Do(gomock.Any()).
+			Return(&http.Response{StatusCode: http.StatusOK, Body: must(testdata.Open("testdata/providers.json"))}, nil),
+		httpMock.EXPECT().
+			Do(gomock.Any()).
+			Return(&http.Response{StatusCode: http.StatusOK, Body: must(testdata.Open("testdata/token.json"))}, nil),
+		httpMock.EXPECT().
+			Do(gomock.Any()).
+			Return(&http.Response{StatusCode: http.StatusOK, Body: must(testdata.Open("testdata/books.json"))}, nil),
+	)
+
+	ctx := context.Background()
+
+	_, err := client.Providers(ctx, "some.user.name")
+	require.NoError(t, err)
+
+	token, err := client.Login(ctx, pbc.LoginRequest{UserName: "some.user.name", Password: "some.password", Provider: "some_provider"})
+	require.NoError(t, err)
+
+	books, err := client.Books(ctx, "some.token", 2, 0)
+	require.NoError(t, err)
+
+	// the response bodies are still readable after dumping
+	assert.Equal(t, "some.access.token", token.AccessToken)
+	assert.Len(t, books.Books, 2)
+
+	logs := out.String()
+
+	for _, secret := range []string{"some.client.secret", "some.password", "some.access.token", "some.refresh.token", "some.token"} {
+		assert.NotContains(t, logs, secret)
+	}
+
+	assert.Contains(t, logs, `"endpoint":"auth/login/some_provider"`)
+	assert.Contains(t, logs, `"endpoint":"books"`)
+	assert.Contains(t, logs, `"status":200`)
+	assert.Contains(t, logs, `"body":`)
+	assert.Contains(t, logs, "some.user.name")
+	assert.Contains(t, logs, "access_token=REDACTED")
+}
+
+func TestClient_WithLogger_Info(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	out := &bytes.Buffer{}
+	client := pbc.New(pbc.WithHTTPClient(httpMock), pbc.WithLogger(slog.New(slog.NewJSONHandler(out, nil))))
+
+	httpMock.EXPECT().
+		Do(gomock.Any()).
+		Return(&http.Response{StatusCode: http.StatusUnauthorized, Body: http.NoBody}, nil)
+
+	_, err := client.Books(context.Background(), "some.token", 2, 0)
+	require.ErrorIs(t, err, pbc.ErrUnauthorized)
+
+	logs := out.String()
+
+	assert.Contains(t, logs, `"level":"WARN"`)
+	assert.Contains(t, logs, `"status":401`)
+	assert.Contains(t, logs, `"duration":`)
+	assert.NotContains(t, logs, `"body":`)
+	assert.NotContains(t, logs, "some.token")
+}
+
+func TestClient_WithLogger_Debug_LargeBodies(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	out := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(out, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := pbc.New(pbc.WithHTTPClient(httpMock), pbc.WithLogger(logger))
+
+	content, book := downloadFixture()
+
+	gomock.InOrder(
+		httpMock.EXPECT().
+			Do(gomock.Any()).
+			Return(&http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{string(pbc.MimeTypeEPUB)}},
+				Body:       io.NopCloser(bytes.NewReader(content)),
+			}, nil),
+		httpMock.EXPECT().
+			Do(gomock.Any()).
+			Return(&http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       io.NopCloser(bytes.NewReader(largeBooks())),
+			}, nil),
+	)
+
+	var buf bytes.Buffer
+
+	require.NoError(t, client.Download(context.Background(), "some.token", book, &buf))
+	assert.Equal(t, content, buf.Bytes())
+
+	count := 0
+
+	_, err := client.StreamBooks(context.Background(), "some.token", 10_000, 0, func(pbc.Book) error {
+		count++
+
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 10_000, count)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 4)
+
+	assert.NotContains(t, lines[1], `"body"`)
+	assert.Contains(t, lines[3], `...(truncated)"`)
+	assert.Less(t, len(lines[3]), 2*len(largeBooks())/10)
+}
+
+func TestClient_WithLogger_Debug_Upload(t *testing.T) {
+	t.Parallel()
+
+	out := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(out, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	fs, client := newFileServer(t, pbc.WithLogger(logger))
+	file := []byte(strings.Repeat("plain text ", 12_000))
+
+	book, err := client.Upload(context.Background(), "some.token", "notes.txt",
+		bytes.NewReader(file), int64(len(file)), pbc.UploadOptions{})
+	require.NoError(t, err)
+
+	// the uploaded file is sent whole, it is not read by the logger
+	assert.Equal(t, file, fs.body("/notes.txt"))
+	assert.Equal(t, pbc.MimeTypeTXT, book.MimeType)
+	assert.NotContains(t, out.String(), "plain text")
+}
+
+func TestClient_WithLogger_Debug_CutSecret(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	out := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(out, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := pbc.New(pbc.WithHTTPClient(httpMock), pbc.WithLogger(logger))
+
+	// the token crosses the end of the logged head of the body
+	secret := strings.Repeat("some.secret.", 100)
+	body := `{"total":0,"items":[],"pad":"` + strings.Repeat("x", 64<<10-600) + `","access_token":"` + secret + `"}`
+
+	httpMock.EXPECT().
+		Do(gomock.Any()).
+		Return(&http.Response{
+			StatusCode:    http.StatusOK,
+			Header:        http.Header{"Content-Type": []string{"application/json"}},
+			ContentLength: -1,
+			Body:          io.NopCloser(strings.NewReader(body)),
+		}, nil)
+
+	_, err := client.Books(context.Background(), "some.token", 2, 0)
+	require.NoError(t, err)
+
+	logs := out.String()
+
+	assert.Contains(t, logs, `...(truncated)"`)
+	assert.NotContains(t, logs, "some.secret")
+	assert.NotContains(t, logs, `"size":`)
+}
@@ -2,7 +2,6 @@ package pocketbook_cloud_client
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -23,10 +22,10 @@ type tokenType string
 const TokenTypeBearer tokenType = "Bearer"
 
 type Token struct {
-	AccessToken  string
-	TokenType    tokenType
-	ExpiresIn    time.Time
-	RefreshToken string
+	AccessToken  string    `json:"access_token"`
+	TokenType    tokenType `json:"token_type"`
+	ExpiresIn    time.Time `json:"expires_at"`
+	RefreshToken string    `json:"refresh_token"`
 }
 
 func (c Client) Login(ctx context.Context, lreq LoginRequest) (Token, error) {
@@ -34,35 +33,51 @@ func (c Client) Login(ctx context.Context, lreq LoginRequest) (Token, error) {
 	q.Set("shop_id", lreq.ShopID)
 	q.Set("username", lreq.UserName)
 	q.Set("password", lreq.Password)
+	q.Set("grant_type", "password")
+
+	t, err := c.grant(ctx, OperationLogin, lreq.Provider, q)
+	if err != nil {
+		return Token{}, fmt.Errorf("%s userName=%s provider=%s: %w", login, lreq.UserName, lreq.Provider, err)
+	}
+
+	return t, nil
+}
+
+// grant requests a new token from the provider login endpoint.
+// Client credentials are appended to the passed form values.
+func (c Client) grant(ctx context.Context, op, provider string, q url.Values) (Token, error) {
 	q.Set("client_id", c.clientID)
 	q.Set("client_secret", c.clientSecret)
-	q.Set("grant_type", "password")
+
+	form := q.Encode()
 
 	req := &http.Request{
 		Method: http.MethodPost,
-		URL:    c.url(login).JoinPath(lreq.Provider),
+		URL:    c.url(login).JoinPath(provider),
 		Header: http.Header{
 			"Content-Type": []string{"application/x-www-form-urlencoded"},
 		},
-		Body: io.NopCloser(strings.NewReader(q.Encode())),
+		Body:          io.NopCloser(strings.NewReader(form)),
+		GetBody:       func() (io.ReadCloser, error) { return io.NopCloser(strings.NewReader(form)), nil },
+		ContentLength: int64(len(form)),
 	}
 
 	req = req.WithContext(ctx)
 
-	body, err := c.req(req)
+	body, err := c.req(op, req)
 	if err != nil {
-		return Token{}, fmt.Errorf("%s userName=%s provider=%s: %w", login, lreq.UserName, lreq.Provider, err)
+		return Token{}, err
 	}
 
 	var data struct {
-		AccessToken  string    `json:"access_token"`
-		TokenType    tokenType `json:"token_type"`
-		ExpiresIn    int       `json:"expires_in"`
-		RefreshToken string    `json:"refresh_token"`
+		AccessToken  string     `json:"access_token"`
+		TokenType    tokenType  `json:"token_type"`
+		ExpiresIn    lenientInt `json:"expires_in"`
+		RefreshToken string     `json:"refresh_token"`
 	}
 
-	if err = json.Unmarshal(body, &data); err != nil {
-		return Token{}, fmt.Errorf("unmarshal response body: %w", err)
+	if err = c.decode(op, body, &data); err != nil {
+		return Token{}, err
 	}
 
 	t := Token{
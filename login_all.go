@@ -0,0 +1,88 @@
+package pocketbook_cloud_client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrPasswordLoginUnsupported is reported for providers which do not allow to login by password.
+var ErrPasswordLoginUnsupported = errors.New("password login is not supported by provider")
+
+// LoginAllResult holds sessions of the providers logged in successfully and failures of the rest.
+type LoginAllResult struct {
+	Sessions []*Session
+	Failures []ProviderFailure
+}
+
+// ProviderFailure is the login error of the provider.
+type ProviderFailure struct {
+	Provider Provider
+	Err      error
+}
+
+// Err returns the joined errors of failures or nil if all providers logged in.
+func (r LoginAllResult) Err() error {
+	errs := make([]error, len(r.Failures))
+	for i, f := range r.Failures {
+		errs[i] = f.Err
+	}
+
+	return errors.Join(errs...)
+}
+
+// LoginAll logs in to every provider of the account concurrently.
+// A failed provider does not block the others, its error is reported in LoginAllResult.Failures.
+// Providers without the password login are reported with ErrPasswordLoginUnsupported.
+// The returned error is not nil only when the providers can not be listed.
+func (c Client) LoginAll(ctx context.Context, userName, password string, opts ...TokenSourceOption) (LoginAllResult, error) {
+	prvs, err := c.Providers(ctx, userName)
+	if err != nil {
+		return LoginAllResult{}, err
+	}
+
+	sessions := make([]*Session, len(prvs))
+	errs := make([]error, len(prvs))
+
+	var wg sync.WaitGroup
+
+	for i, prv := range prvs {
+		if !prv.PasswordLogin() {
+			errs[i] = fmt.Errorf("provider=%s loggedBy=%s: %w", prv.Alias, prv.LoggedBy, ErrPasswordLoginUnsupported)
+
+			continue
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			req := LoginRequest{
+				ShopID:   prv.ShopID,
+				UserName: userName,
+				Password: password,
+				Provider: prv.Alias,
+			}
+
+			sessions[i], errs[i] = c.LoginSession(ctx, req, opts...)
+		}()
+	}
+
+	wg.Wait()
+
+	var result LoginAllResult
+
+	for i, prv := range prvs {
+		if errs[i] != nil {
+			result.Failures = append(result.Failures, ProviderFailure{Provider: prv, Err: errs[i]})
+
+			continue
+		}
+
+		result.Sessions = append(result.Sessions, sessions[i])
+	}
+
+	return result, nil
+}
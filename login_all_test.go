@@ -0,0 +1,95 @@
+package pocketbook_cloud_client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	pbc "github.com/micronull/pocketbook-cloud-client"
+	"github.com/micronull/pocketbook-cloud-client/mocks"
+)
+
+func TestClient_LoginAll(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+
+	httpMock.EXPECT().
+		Do(gomock.Any()).
+		DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			switch req.URL.Path {
+			case "/api/v1.0/auth/login":
+				return &http.Response{StatusCode: http.StatusOK, Body: must(testdata.Open("testdata/providers.json"))}, nil
+			case "/api/v1.0/auth/login/pocketbook_de":
+				assert.Equal(t, "1", req.FormValue("shop_id"))
+				assert.Equal(t, "some.user.name", req.FormValue("username"))
+				assert.Equal(t, "some.password", req.FormValue("password"))
+
+				return &http.Response{StatusCode: http.StatusOK, Body: must(testdata.Open("testdata/token.json"))}, nil
+			}
+
+			return nil, errors.New("unexpected request " + req.URL.Path)
+		}).
+		Times(2)
+
+	got, err := client.LoginAll(context.Background(), "some.user.name", "some.password")
+	require.NoError(t, err)
+
+	require.Len(t, got.Sessions, 1)
+	assert.Equal(t, "pocketbook_de", got.Sessions[0].Provider())
+	assert.Equal(t, "1", got.Sessions[0].ShopID())
+
+	require.Len(t, got.Failures, 1)
+	assert.Equal(t, "bookland_ru", got.Failures[0].Provider.Alias)
+	require.ErrorIs(t, got.Failures[0].Err, pbc.ErrPasswordLoginUnsupported)
+	require.ErrorIs(t, got.Err(), pbc.ErrPasswordLoginUnsupported)
+}
+
+func TestClient_LoginAll_LoginError(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+	errExpected := errors.New("something went wrong")
+
+	gomock.InOrder(
+		httpMock.EXPECT().
+			Do(gomock.Any()).
+			Return(&http.Response{StatusCode: http.StatusOK, Body: must(testdata.Open("testdata/providers.json"))}, nil),
+		httpMock.EXPECT().
+			Do(gomock.Any()).
+			Return(nil, errExpected),
+	)
+
+	got, err := client.LoginAll(context.Background(), "some.user.name", "some.password")
+	require.NoError(t, err)
+
+	assert.Empty(t, got.Sessions)
+	require.Len(t, got.Failures, 2)
+	assert.Equal(t, "pocketbook_de", got.Failures[0].Provider.Alias)
+	require.ErrorIs(t, got.Failures[0].Err, errExpected)
+}
+
+func TestClient_LoginAll_ProvidersError(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+	errExpected := errors.New("something went wrong")
+
+	httpMock.EXPECT().
+		Do(gomock.Any()).
+		Return(nil, errExpected)
+
+	_, err := client.LoginAll(context.Background(), "some.user.name", "some.password")
+	require.ErrorIs(t, err, errExpected)
+}
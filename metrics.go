@@ -0,0 +1,152 @@
+package pocketbook_cloud_client
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultLatencyBuckets are the upper bounds in seconds of the latency histogram buckets.
+var DefaultLatencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// Metrics is the Observer collecting Prometheus style metrics of request attempts.
+// It serves them in the Prometheus text exposition format as http.Handler:
+//
+//	pocketbook_client_requests_total{operation, status, error_class} counter
+//	pocketbook_client_retries_total{operation} counter
+//	pocketbook_client_request_duration_seconds{operation} histogram
+type Metrics struct {
+	buckets []float64
+
+	mu        sync.Mutex
+	requests  map[requestsKey]uint64
+	retries   map[string]uint64
+	latencies map[string]*histogram
+}
+
+type requestsKey struct {
+	operation  string
+	status     int
+	errorClass ErrorClass
+}
+
+type histogram struct {
+	counts []uint64 // per bucket, not cumulative
+	count  uint64
+	sum    float64
+}
+
+// NewMetrics returns the metrics with the latency histogram of the buckets or DefaultLatencyBuckets.
+func NewMetrics(buckets ...float64) *Metrics {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+
+	buckets = slices.Clone(buckets)
+	slices.Sort(buckets)
+
+	return &Metrics{
+		buckets:   buckets,
+		requests:  make(map[requestsKey]uint64),
+		retries:   make(map[string]uint64),
+		latencies: make(map[string]*histogram),
+	}
+}
+
+func (m *Metrics) Observe(_ context.Context, o Observation) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests[requestsKey{operation: o.Operation, status: o.StatusCode, errorClass: o.ErrorClass}]++
+
+	if o.Attempt > 1 {
+		m.retries[o.Operation]++
+	}
+
+	h, ok := m.latencies[o.Operation]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(m.buckets))}
+		m.latencies[o.Operation] = h
+	}
+
+	sec := o.Latency.Seconds()
+	h.count++
+	h.sum += sec
+
+	if i, _ := slices.BinarySearch(m.buckets, sec); i < len(m.buckets) {
+		h.counts[i]++
+	}
+}
+
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	bw := bufio.NewWriter(w)
+	m.write(bw)
+	_ = bw.Flush()
+}
+
+func (m *Metrics) write(w *bufio.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fmt.Fprintln(w, "# HELP pocketbook_client_requests_total Request attempts by operation, status code and error class.")
+	fmt.Fprintln(w, "# TYPE pocketbook_client_requests_total counter")
+
+	keys := make([]requestsKey, 0, len(m.requests))
+	for k := range m.requests {
+		keys = append(keys, k)
+	}
+
+	slices.SortFunc(keys, func(a, b requestsKey) int {
+		return cmp.Or(
+			cmp.Compare(a.operation, b.operation),
+			cmp.Compare(a.status, b.status),
+			cmp.Compare(a.errorClass, b.errorClass),
+		)
+	})
+
+	for _, k := range keys {
+		fmt.Fprintf(w, "pocketbook_client_requests_total{operation=%s,status=%s,error_class=%s} %d\n",
+			label(k.operation), label(strconv.Itoa(k.status)), label(string(k.errorClass)), m.requests[k])
+	}
+
+	fmt.Fprintln(w, "# HELP pocketbook_client_retries_total Retried request attempts by operation.")
+	fmt.Fprintln(w, "# TYPE pocketbook_client_retries_total counter")
+
+	for _, op := range slices.Sorted(maps.Keys(m.retries)) {
+		fmt.Fprintf(w, "pocketbook_client_retries_total{operation=%s} %d\n", label(op), m.retries[op])
+	}
+
+	fmt.Fprintln(w, "# HELP pocketbook_client_request_duration_seconds Latency of request attempts by operation.")
+	fmt.Fprintln(w, "# TYPE pocketbook_client_request_duration_seconds histogram")
+
+	for _, op := range slices.Sorted(maps.Keys(m.latencies)) {
+		h := m.latencies[op]
+
+		var cumulative uint64
+
+		for i, le := range m.buckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(w, "pocketbook_client_request_duration_seconds_bucket{operation=%s,le=%s} %d\n",
+				label(op), label(strconv.FormatFloat(le, 'g', -1, 64)), cumulative)
+		}
+
+		fmt.Fprintf(w, "pocketbook_client_request_duration_seconds_bucket{operation=%s,le=\"+Inf\"} %d\n", label(op), h.count)
+		fmt.Fprintf(w, "pocketbook_client_request_duration_seconds_sum{operation=%s} %s\n", label(op), strconv.FormatFloat(h.sum, 'g', -1, 64))
+		fmt.Fprintf(w, "pocketbook_client_request_duration_seconds_count{operation=%s} %d\n", label(op), h.count)
+	}
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func label(v string) string {
+	return `"` + labelEscaper.Replace(v) + `"`
+}
@@ -0,0 +1,78 @@
+package pocketbook_cloud_client
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// Middleware wraps the Doer to handle requests and responses.
+type Middleware func(next Doer) Doer
+
+// DoerFunc is an adapter to use ordinary functions as Doer.
+type DoerFunc func(req *http.Request) (*http.Response, error)
+
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// RequestIDHeader is the header of the request id set by RequestID middleware.
+const RequestIDHeader = "X-Request-Id"
+
+// UserAgent sets the User-Agent header of requests.
+func UserAgent(ua string) Middleware {
+	return Headers(http.Header{"User-Agent": []string{ua}})
+}
+
+// Headers sets the headers of requests, replacing the values of the same keys.
+func Headers(h http.Header) Middleware {
+	h = h.Clone()
+
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			req = cloneRequest(req)
+
+			for k, v := range h {
+				req.Header[http.CanonicalHeaderKey(k)] = v
+			}
+
+			return next.Do(req)
+		})
+	}
+}
+
+// RequestID sets the X-Request-Id header of requests not having it.
+// The id is generated by gen or is a random hex string if gen is nil.
+func RequestID(gen func() string) Middleware {
+	if gen == nil {
+		gen = randomID
+	}
+
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get(RequestIDHeader) == "" {
+				req = cloneRequest(req)
+				req.Header.Set(RequestIDHeader, gen())
+			}
+
+			return next.Do(req)
+		})
+	}
+}
+
+// cloneRequest returns the copy of the request safe to modify headers.
+func cloneRequest(req *http.Request) *http.Request {
+	r := req.Clone(req.Context())
+	if r.Header == nil {
+		r.Header = http.Header{}
+	}
+
+	return r
+}
+
+func randomID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
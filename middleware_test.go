@@ -0,0 +1,83 @@
+package pocketbook_cloud_client_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	pbc "github.com/micronull/pocketbook-cloud-client"
+	"github.com/micronull/pocketbook-cloud-client/mocks"
+)
+
+func TestClient_WithMiddleware(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+
+	var order []string
+
+	trace := func(name string) pbc.Middleware {
+		return func(next pbc.Doer) pbc.Doer {
+			return pbc.DoerFunc(func(req *http.Request) (*http.Response, error) {
+				order = append(order, name+" request")
+				rsp, err := next.Do(req)
+				order = append(order, name+" response")
+
+				return rsp, err
+			})
+		}
+	}
+
+	client := pbc.New(
+		pbc.WithMiddleware(trace("first"), trace("second")),
+		pbc.WithHTTPClient(httpMock),
+		pbc.WithMiddleware(
+			pbc.UserAgent("some.user.agent"),
+			pbc.RequestID(func() string { return "some.request.id" }),
+			pbc.Headers(http.Header{"x-some-header": []string{"some.value"}}),
+		),
+	)
+
+	httpMock.EXPECT().
+		Do(mock.MatchedBy(func(req *http.Request) bool {
+			return isAllTrue(
+				assert.Equal(t, "some.user.agent", req.Header.Get("User-Agent")),
+				assert.Equal(t, "some.request.id", req.Header.Get(pbc.RequestIDHeader)),
+				assert.Equal(t, "some.value", req.Header.Get("X-Some-Header")),
+				assert.Equal(t, "client_id=&client_secret=&username=some.user.name", req.URL.Query().Encode()),
+			)
+		})).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: must(testdata.Open("testdata/providers.json"))}, nil)
+
+	_, err := client.Providers(context.Background(), "some.user.name")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"first request", "second request", "second response", "first response"}, order)
+}
+
+func TestRequestID_Random(t *testing.T) {
+	t.Parallel()
+
+	var ids []string
+
+	doer := pbc.RequestID(nil)(pbc.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		ids = append(ids, req.Header.Get(pbc.RequestIDHeader))
+
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	}))
+
+	for range 2 {
+		_, err := doer.Do(&http.Request{})
+		require.NoError(t, err)
+	}
+
+	require.Len(t, ids, 2)
+	assert.Len(t, ids[0], 32)
+	assert.NotEqual(t, ids[0], ids[1])
+}
@@ -3,7 +3,7 @@
 //
 // Generated by this command:
 //
-//	mockgen -source client.go -destination mocks/client.go -package mocks -mock_names doer=MockDoer
+//	mockgen -source client.go -destination mocks/client.go -package mocks -mock_names Doer=MockDoer
 //
 
 // Package mocks is a generated GoMock package.
@@ -16,7 +16,7 @@ import (
 	gomock "go.uber.org/mock/gomock"
 )
 
-// MockDoer is a mock of doer interface.
+// MockDoer is a mock of Doer interface.
 type MockDoer struct {
 	ctrl     *gomock.Controller
 	recorder *MockDoerMockRecorder
@@ -0,0 +1,124 @@
+package pocketbook_cloud_client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Operations reported to the Observer.
+const (
+	OperationLogin      = "Login"
+	OperationRefresh    = "Refresh"
+	OperationProviders  = "Providers"
+	OperationBooks      = "Books"
+	OperationDownload   = "Download"
+	OperationCover      = "Cover"
+	OperationUpload     = "Upload"
+	OperationDeleteBook = "DeleteBook"
+	OperationRenameBook = "RenameBook"
+	OperationMoveBook   = "MoveBook"
+)
+
+// ErrorClass is the coarse kind of the attempt failure, suitable as a metric label.
+type ErrorClass string
+
+const (
+	ErrorClassNone         ErrorClass = ""
+	ErrorClassCanceled     ErrorClass = "canceled"
+	ErrorClassTransport    ErrorClass = "transport"
+	ErrorClassUnauthorized ErrorClass = "unauthorized"
+	ErrorClassNotFound     ErrorClass = "not_found"
+	ErrorClassRateLimited  ErrorClass = "rate_limited"
+	ErrorClassClient       ErrorClass = "client"
+	ErrorClassServer       ErrorClass = "server"
+)
+
+// Observation is the result of a single request attempt of the operation.
+type Observation struct {
+	// Operation is the name of the client method, e.g. OperationBooks.
+	Operation string
+	// Endpoint is the path of the request relative to the API root.
+	Endpoint string
+	// Attempt is the number of the attempt starting from 1.
+	Attempt int
+	// StatusCode is the http status code of the response, 0 when there is no response.
+	StatusCode int
+	Latency    time.Duration
+	ErrorClass ErrorClass
+}
+
+// Observer is notified about every request attempt, e.g. to export metrics or traces.
+// It is called from the goroutine of the request and must be safe for concurrent use.
+type Observer interface {
+	Observe(ctx context.Context, o Observation)
+}
+
+// StartObserver is the Observer also notified before every request attempt, e.g. to start a trace span.
+// The returned context is the context of the request, so the middleware and the transport see the span,
+// and it is passed to Observe when the attempt is done.
+type StartObserver interface {
+	Observer
+	Start(ctx context.Context, op, endpoint string) context.Context
+}
+
+// ObserverFunc is an adapter to use ordinary functions as Observer.
+type ObserverFunc func(ctx context.Context, o Observation)
+
+func (f ObserverFunc) Observe(ctx context.Context, o Observation) {
+	f(ctx, o)
+}
+
+// startAttempt returns the request carrying the context returned by StartObserver.
+func (c Client) startAttempt(op string, req *http.Request) *http.Request {
+	so, ok := c.observer.(StartObserver)
+	if !ok {
+		return req
+	}
+
+	return req.WithContext(so.Start(req.Context(), op, c.endpoint(req.URL)))
+}
+
+func (c Client) observe(ctx context.Context, op string, req *http.Request, attempt int, start time.Time, rsp *http.Response, err error) {
+	if c.observer == nil {
+		return
+	}
+
+	o := Observation{
+		Operation: op,
+		Endpoint:  c.endpoint(req.URL),
+		Attempt:   attempt,
+		Latency:   time.Since(start),
+	}
+
+	if err != nil {
+		o.ErrorClass = ErrorClassTransport
+
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			o.ErrorClass = ErrorClassCanceled
+		}
+	} else {
+		o.StatusCode = rsp.StatusCode
+		o.ErrorClass = statusErrorClass(rsp.StatusCode)
+	}
+
+	c.observer.Observe(ctx, o)
+}
+
+func statusErrorClass(code int) ErrorClass {
+	switch {
+	case code < http.StatusBadRequest:
+		return ErrorClassNone
+	case code == http.StatusUnauthorized:
+		return ErrorClassUnauthorized
+	case code == http.StatusNotFound:
+		return ErrorClassNotFound
+	case code == http.StatusTooManyRequests:
+		return ErrorClassRateLimited
+	case code >= http.StatusInternalServerError:
+		return ErrorClassServer
+	}
+
+	return ErrorClassClient
+}
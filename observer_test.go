@@ -0,0 +1,183 @@
+package pocketbook_cloud_client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	pbc "github.com/micronull/pocketbook-cloud-client"
+	"github.com/micronull/pocketbook-cloud-client/mocks"
+)
+
+func TestClient_WithObserver(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+
+	var (
+		mu  sync.Mutex
+		got []pbc.Observation
+	)
+
+	observer := pbc.ObserverFunc(func(_ context.Context, o pbc.Observation) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		got = append(got, o)
+	})
+
+	client := pbc.New(
+		pbc.WithHTTPClient(httpMock),
+		pbc.WithRetry(testRetryPolicy()),
+		pbc.WithObserver(observer),
+	)
+
+	gomock.InOrder(
+		httpMock.EXPECT().
+			Do(gomock.Any()).
+			Return(&http.Response{StatusCode: http.StatusBadGateway, Body: http.NoBody}, nil),
+		httpMock.EXPECT().
+			Do(gomock.Any()).
+			Return(&http.Response{StatusCode: http.StatusOK, Body: must(testdata.Open("testdata/books.json"))}, nil),
+		httpMock.EXPECT().
+			Do(gomock.Any()).
+			Return(nil, context.Canceled),
+	)
+
+	_, err := client.Books(context.Background(), "some.token", 2, 0)
+	require.NoError(t, err)
+
+	_, err = client.Login(context.Background(), pbc.LoginRequest{Provider: "some_provider"})
+	require.ErrorIs(t, err, context.Canceled)
+
+	require.Len(t, got, 3)
+
+	assert.Equal(t, pbc.OperationBooks, got[0].Operation)
+	assert.Equal(t, "books", got[0].Endpoint)
+	assert.Equal(t, 1, got[0].Attempt)
+	assert.Equal(t, http.StatusBadGateway, got[0].StatusCode)
+	assert.Equal(t, pbc.ErrorClassServer, got[0].ErrorClass)
+
+	assert.Equal(t, pbc.OperationBooks, got[1].Operation)
+	assert.Equal(t, 2, got[1].Attempt)
+	assert.Equal(t, http.StatusOK, got[1].StatusCode)
+	assert.Equal(t, pbc.ErrorClassNone, got[1].ErrorClass)
+
+	assert.Equal(t, pbc.OperationLogin, got[2].Operation)
+	assert.Equal(t, "auth/login/some_provider", got[2].Endpoint)
+	assert.Equal(t, 0, got[2].StatusCode)
+	assert.Equal(t, pbc.ErrorClassCanceled, got[2].ErrorClass)
+}
+
+type spanKey struct{}
+
+// tracer starts a span named after the attempt and ends it when the attempt is observed.
+type tracer struct {
+	mu    sync.Mutex
+	spans int
+	ended []string
+}
+
+func (tr *tracer) Start(ctx context.Context, op, endpoint string) context.Context {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	tr.spans++
+
+	return context.WithValue(ctx, spanKey{}, op+" "+endpoint+" #"+strconv.Itoa(tr.spans))
+}
+
+func (tr *tracer) Observe(ctx context.Context, _ pbc.Observation) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	span, _ := ctx.Value(spanKey{}).(string)
+	tr.ended = append(tr.ended, span)
+}
+
+func TestClient_WithObserver_Start(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	tr := &tracer{}
+
+	client := pbc.New(
+		pbc.WithHTTPClient(httpMock),
+		pbc.WithRetry(testRetryPolicy()),
+		pbc.WithObserver(tr),
+	)
+
+	var sent []string
+
+	httpMock.EXPECT().
+		Do(gomock.Any()).
+		DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			span, _ := req.Context().Value(spanKey{}).(string)
+			sent = append(sent, span)
+
+			if len(sent) == 1 {
+				return &http.Response{StatusCode: http.StatusBadGateway, Body: http.NoBody}, nil
+			}
+
+			return &http.Response{StatusCode: http.StatusOK, Body: must(testdata.Open("testdata/books.json"))}, nil
+		}).
+		Times(2)
+
+	_, err := client.Books(context.Background(), "some.token", 2, 0)
+	require.NoError(t, err)
+
+	// every attempt is sent and observed within its own span
+	assert.Equal(t, []string{"Books books #1", "Books books #2"}, sent)
+	assert.Equal(t, sent, tr.ended)
+}
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	metrics := pbc.NewMetrics(0.1, 1)
+	ctx := context.Background()
+
+	metrics.Observe(ctx, pbc.Observation{Operation: pbc.OperationBooks, Attempt: 1, StatusCode: 502, Latency: 50 * time.Millisecond, ErrorClass: pbc.ErrorClassServer})
+	metrics.Observe(ctx, pbc.Observation{Operation: pbc.OperationBooks, Attempt: 2, StatusCode: 200, Latency: 500 * time.Millisecond})
+	metrics.Observe(ctx, pbc.Observation{Operation: pbc.OperationLogin, Attempt: 1, Latency: 2 * time.Second, ErrorClass: pbc.ErrorClassTransport})
+
+	rec := httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/plain")
+
+	expected := `# HELP pocketbook_client_requests_total Request attempts by operation, status code and error class.
+# TYPE pocketbook_client_requests_total counter
+pocketbook_client_requests_total{operation="Books",status="200",error_class=""} 1
+pocketbook_client_requests_total{operation="Books",status="502",error_class="server"} 1
+pocketbook_client_requests_total{operation="Login",status="0",error_class="transport"} 1
+# HELP pocketbook_client_retries_total Retried request attempts by operation.
+# TYPE pocketbook_client_retries_total counter
+pocketbook_client_retries_total{operation="Books"} 1
+# HELP pocketbook_client_request_duration_seconds Latency of request attempts by operation.
+# TYPE pocketbook_client_request_duration_seconds histogram
+pocketbook_client_request_duration_seconds_bucket{operation="Books",le="0.1"} 1
+pocketbook_client_request_duration_seconds_bucket{operation="Books",le="1"} 2
+pocketbook_client_request_duration_seconds_bucket{operation="Books",le="+Inf"} 2
+pocketbook_client_request_duration_seconds_sum{operation="Books"} 0.55
+pocketbook_client_request_duration_seconds_count{operation="Books"} 2
+pocketbook_client_request_duration_seconds_bucket{operation="Login",le="0.1"} 0
+pocketbook_client_request_duration_seconds_bucket{operation="Login",le="1"} 0
+pocketbook_client_request_duration_seconds_bucket{operation="Login",le="+Inf"} 1
+pocketbook_client_request_duration_seconds_sum{operation="Login"} 2
+pocketbook_client_request_duration_seconds_count{operation="Login"} 1
+`
+
+	assert.Equal(t, expected, rec.Body.String())
+}
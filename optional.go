@@ -0,0 +1,132 @@
+package pocketbook_cloud_client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Optional is the value which may be missing or null in the API response.
+// The zero Optional is missing, so the field absent in JSON stays missing.
+type Optional[T any] struct {
+	value   T
+	valid   bool
+	present bool
+}
+
+// Some returns the Optional set to the value.
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{value: v, valid: true, present: true}
+}
+
+// Null returns the Optional explicitly set to null.
+func Null[T any]() Optional[T] {
+	return Optional[T]{present: true}
+}
+
+// Get returns the value and whether it is set.
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.valid
+}
+
+// Valid reports whether the value is set, neither missing nor null.
+func (o Optional[T]) Valid() bool {
+	return o.valid
+}
+
+// IsNull reports whether the value is explicitly null.
+func (o Optional[T]) IsNull() bool {
+	return o.present && !o.valid
+}
+
+// Missing reports whether the value is absent at all.
+func (o Optional[T]) Missing() bool {
+	return !o.present
+}
+
+// ValueOr returns the value if it is set or def otherwise.
+func (o Optional[T]) ValueOr(def T) T {
+	if o.valid {
+		return o.value
+	}
+
+	return def
+}
+
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.valid {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(o.value)
+}
+
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*o = Null[T]()
+
+		return nil
+	}
+
+	var v T
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*o = Some(v)
+
+	return nil
+}
+
+// marshalPresent marshals the struct like encoding/json does, but omits the Optional fields which are missing.
+// Thus missing and null values survive the round trip. The passed value must not implement json.Marshaler.
+func marshalPresent(v any) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	rt := rv.Type()
+
+	buf := bytes.NewBufferString("{")
+
+	for i := range rt.NumField() {
+		f := rt.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = f.Name
+		}
+
+		fv := rv.Field(i)
+
+		if m, ok := fv.Interface().(interface{ Missing() bool }); ok && m.Missing() {
+			continue
+		}
+
+		data, err := json.Marshal(fv.Interface())
+		if err != nil {
+			return nil, fmt.Errorf("marshal %s: %w", f.Name, err)
+		}
+
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+
+		key, _ := json.Marshal(name)
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(data)
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
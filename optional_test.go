@@ -0,0 +1,56 @@
+package pocketbook_cloud_client_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pbc "github.com/micronull/pocketbook-cloud-client"
+)
+
+func TestOptional_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	var got struct {
+		Missing pbc.Optional[int] `json:"missing"`
+		Null    pbc.Optional[int] `json:"null"`
+		Zero    pbc.Optional[int] `json:"zero"`
+		Value   pbc.Optional[int] `json:"value"`
+	}
+
+	require.NoError(t, json.Unmarshal([]byte(`{"null":null,"zero":0,"value":42}`), &got))
+
+	assert.True(t, got.Missing.Missing())
+	assert.False(t, got.Missing.IsNull())
+	assert.False(t, got.Missing.Valid())
+
+	assert.False(t, got.Null.Missing())
+	assert.True(t, got.Null.IsNull())
+	assert.False(t, got.Null.Valid())
+
+	v, ok := got.Zero.Get()
+	assert.True(t, ok)
+	assert.Equal(t, 0, v)
+
+	assert.Equal(t, 42, got.Value.ValueOr(1))
+	assert.Equal(t, 1, got.Null.ValueOr(1))
+
+	require.Error(t, json.Unmarshal([]byte(`{"value":"42"}`), &got))
+}
+
+func TestOptional_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	data := struct {
+		Missing pbc.Optional[string] `json:"missing"`
+		Null    pbc.Optional[string] `json:"null"`
+		Value   pbc.Optional[string] `json:"value"`
+	}{
+		Null:  pbc.Null[string](),
+		Value: pbc.Some("some"),
+	}
+
+	assert.JSONEq(t, `{"missing":null,"null":null,"value":"some"}`, string(must(json.Marshal(data))))
+}
@@ -1,13 +1,33 @@
 package pocketbook_cloud_client
 
+import (
+	"log/slog"
+	"net/url"
+	"strings"
+)
+
 type Option func(*Client)
 
-func WithHTTPClient(client doer) Option {
+func WithHTTPClient(client Doer) Option {
 	return func(c *Client) {
 		c.http = client
 	}
 }
 
+// WithBaseURL sets the root of the API, e.g. the address of a local stand-in server.
+// By default it is DefaultScheme://DefaultHost/DefaultPath.
+func WithBaseURL(u *url.URL) Option {
+	return func(c *Client) {
+		c.scheme = u.Scheme
+		c.host = u.Host
+		c.path = "/" + strings.Trim(u.Path, "/") + "/"
+
+		if c.path == "//" {
+			c.path = "/"
+		}
+	}
+}
+
 func WithClientID(id string) Option {
 	return func(c *Client) {
 		c.clientID = id
@@ -19,3 +39,71 @@ func WithClientSecret(sec string) Option {
 		c.clientSecret = sec
 	}
 }
+
+// WithRetry sets the policy of retrying failed requests. By default requests are not retried.
+func WithRetry(p RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = p
+	}
+}
+
+// WithRateLimit limits requests of all goroutines sharing the Client.
+// Auth endpoints (login, refresh, providers) and data endpoints have separate limits.
+func WithRateLimit(auth, data Limit) Option {
+	return func(c *Client) {
+		c.limits = &rateLimits{
+			auth: newBucket(auth),
+			data: newBucket(data),
+		}
+	}
+}
+
+// WithMiddleware wraps the http client by middlewares.
+// The first middleware is the outermost one, it gets the request first and the response last.
+// Middlewares are called for every attempt of the request.
+func WithMiddleware(mws ...Middleware) Option {
+	return func(c *Client) {
+		c.middlewares = append(c.middlewares, mws...)
+	}
+}
+
+// WithLogger logs every request attempt and its response: method, endpoint, status, duration and size.
+// Passwords, client secrets and tokens are always redacted.
+// Redacted headers and bodies are logged when the logger is enabled for the debug level.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithObserver sets the observer of every request attempt.
+// The observer implementing StartObserver is also notified before the attempt.
+func WithObserver(o Observer) Option {
+	return func(c *Client) {
+		c.observer = o
+	}
+}
+
+// WithStrictDecoding reports every difference of responses from the expected shape:
+// unknown fields and values of unexpected JSON types, even if they were coerced.
+// The error returned by report fails the request, nil keeps the issue as a warning.
+// A nil report fails the request on the first issue.
+// By default responses are decoded leniently: unknown fields are ignored
+// and coercible types, like a number instead of a string, are silently accepted.
+func WithStrictDecoding(report func(DecodeIssue) error) Option {
+	if report == nil {
+		report = func(i DecodeIssue) error { return i }
+	}
+
+	return func(c *Client) {
+		c.strict = report
+	}
+}
+
+// WithMaxResponseSize limits the size of JSON response bodies in bytes, downloaded files are not limited,
+// larger responses fail with ErrResponseTooLarge. By default the size is not limited.
+func WithMaxResponseSize(n int64) Option {
+	return func(c *Client) {
+		c.maxResponse = n
+	}
+}
@@ -2,18 +2,24 @@ package pocketbook_cloud_client
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
+	"slices"
+	"strings"
 )
 
 type Provider struct {
-	Alias    string
-	Name     string
-	ShopID   string
-	Icon     string
-	IconEink string
-	LoggedBy string
+	Alias    string `json:"alias"`
+	Name     string `json:"name"`
+	ShopID   string `json:"shop_id"`
+	Icon     string `json:"icon"`
+	IconEink string `json:"icon_eink"`
+	LoggedBy string `json:"logged_by"`
+}
+
+// PasswordLogin reports whether the provider allows to login by user name and password.
+func (p Provider) PasswordLogin() bool {
+	return slices.Contains(strings.Split(p.LoggedBy, "|"), "password")
 }
 
 // Providers getting allowed providers list.
@@ -35,24 +41,24 @@ func (c Client) Providers(ctx context.Context, userName string) ([]Provider, err
 
 	req = req.WithContext(ctx)
 
-	body, err := c.req(req)
+	body, err := c.req(OperationProviders, req)
 	if err != nil {
 		return nil, fmt.Errorf("get providers userName=%s: %w", userName, err)
 	}
 
 	var data struct {
 		Providers []struct {
-			Alias    string `json:"alias"`
-			Name     string `json:"name"`
-			ShopID   string `json:"shop_id"`
-			Icon     string `json:"icon"`
-			IconEink string `json:"icon_eink"`
-			LoggedBy string `json:"logged_by"`
+			Alias    string        `json:"alias"`
+			Name     string        `json:"name"`
+			ShopID   lenientString `json:"shop_id"`
+			Icon     string        `json:"icon"`
+			IconEink string        `json:"icon_eink"`
+			LoggedBy string        `json:"logged_by"`
 		} `json:"providers"`
 	}
 
-	if err = json.Unmarshal(body, &data); err != nil {
-		return nil, fmt.Errorf("unmarshal response body: %w", err)
+	if err = c.decode(OperationProviders, body, &data); err != nil {
+		return nil, fmt.Errorf("get providers userName=%s: %w", userName, err)
 	}
 
 	result := make([]Provider, len(data.Providers))
@@ -60,7 +66,7 @@ func (c Client) Providers(ctx context.Context, userName string) ([]Provider, err
 		result[i] = Provider{
 			Alias:    p.Alias,
 			Name:     p.Name,
-			ShopID:   p.ShopID,
+			ShopID:   string(p.ShopID),
 			Icon:     p.Icon,
 			IconEink: p.IconEink,
 			LoggedBy: p.LoggedBy,
@@ -113,3 +113,25 @@ func TestClient_Providers_Error(t *testing.T) {
 	_, err := client.Providers(context.Background(), "some")
 	require.ErrorIs(t, err, errExpected)
 }
+
+func TestProvider_PasswordLogin(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		loggedBy string
+		want     bool
+	}{
+		{loggedBy: "password", want: true},
+		{loggedBy: "facebook|password", want: true},
+		{loggedBy: "facebook|gmail", want: false},
+		{loggedBy: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.loggedBy, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, pbc.Provider{LoggedBy: tt.loggedBy}.PasswordLogin())
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package pocketbook_cloud_client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limit is the rate of the token bucket. Zero Rate means no limit.
+type Limit struct {
+	// Rate is the number of requests per second.
+	Rate float64
+	// Burst is the number of requests allowed at once. Values less than 1 are treated as 1.
+	Burst int
+}
+
+// rateLimits holds buckets shared by all copies of the Client.
+type rateLimits struct {
+	auth *bucket
+	data *bucket
+}
+
+// wait blocks until the request is allowed by the limit of its endpoint.
+func (l *rateLimits) wait(req *http.Request, endpoint string) error {
+	if l == nil {
+		return nil
+	}
+
+	b := l.data
+	if strings.HasPrefix(endpoint, login) {
+		b = l.auth
+	}
+
+	if err := b.wait(req.Context()); err != nil {
+		return fmt.Errorf("rate limit: %w", err)
+	}
+
+	return nil
+}
+
+type bucket struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newBucket(l Limit) *bucket {
+	if l.Rate <= 0 {
+		return nil
+	}
+
+	burst := float64(max(l.Burst, 1))
+
+	return &bucket{
+		rate:   l.Rate,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// wait reserves a token and sleeps until it is available.
+// The reservation is returned when the context is done before.
+func (b *bucket) wait(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+
+	now := time.Now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+
+	var d time.Duration
+	if b.tokens < 0 {
+		d = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+
+	b.mu.Unlock()
+
+	if d == 0 {
+		return nil
+	}
+
+	if err := sleep(ctx, d); err != nil {
+		b.mu.Lock()
+		b.tokens = min(b.burst, b.tokens+1)
+		b.mu.Unlock()
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package pocketbook_cloud_client_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	pbc "github.com/micronull/pocketbook-cloud-client"
+	"github.com/micronull/pocketbook-cloud-client/mocks"
+)
+
+func TestClient_RateLimit(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(
+		pbc.WithHTTPClient(httpMock),
+		pbc.WithRateLimit(pbc.Limit{}, pbc.Limit{Rate: 20, Burst: 2}),
+	)
+
+	httpMock.EXPECT().
+		Do(gomock.Any()).
+		DoAndReturn(func(*http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: must(testdata.Open("testdata/books.json"))}, nil
+		}).
+		Times(6)
+
+	start := time.Now()
+
+	var wg sync.WaitGroup
+
+	for range 6 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := client.Books(context.Background(), "some.token", 1, 0)
+			assert.NoError(t, err)
+		}()
+	}
+
+	wg.Wait()
+
+	// 2 requests of the burst and 4 requests by 50ms
+	assert.GreaterOrEqual(t, time.Since(start), 190*time.Millisecond)
+}
+
+func TestClient_RateLimit_Separate(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(
+		pbc.WithHTTPClient(httpMock),
+		pbc.WithRateLimit(pbc.Limit{Rate: 0.001, Burst: 1}, pbc.Limit{}),
+	)
+
+	httpMock.EXPECT().
+		Do(gomock.Any()).
+		DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path == "/api/v1.0/books" {
+				return &http.Response{StatusCode: http.StatusOK, Body: must(testdata.Open("testdata/books.json"))}, nil
+			}
+
+			return &http.Response{StatusCode: http.StatusOK, Body: must(testdata.Open("testdata/providers.json"))}, nil
+		}).
+		Times(3)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := client.Providers(ctx, "some.user.name")
+	require.NoError(t, err)
+
+	// data requests are not limited by the exhausted auth limit
+	_, err = client.Books(ctx, "some.token", 1, 0)
+	require.NoError(t, err)
+
+	_, err = client.Books(ctx, "some.token", 1, 0)
+	require.NoError(t, err)
+}
+
+func TestClient_RateLimit_ContextCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(
+		pbc.WithHTTPClient(httpMock),
+		pbc.WithRateLimit(pbc.Limit{Rate: 0.001, Burst: 1}, pbc.Limit{}),
+	)
+
+	httpMock.EXPECT().
+		Do(gomock.Any()).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: must(testdata.Open("testdata/providers.json"))}, nil)
+
+	_, err := client.Providers(context.Background(), "some.user.name")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err = client.Providers(ctx, "some.user.name")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,26 @@
+package pocketbook_cloud_client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+type RefreshRequest struct {
+	Provider     string
+	RefreshToken string
+}
+
+// Refresh exchanges a refresh token for a new token.
+func (c Client) Refresh(ctx context.Context, rreq RefreshRequest) (Token, error) {
+	q := url.Values{}
+	q.Set("refresh_token", rreq.RefreshToken)
+	q.Set("grant_type", "refresh_token")
+
+	t, err := c.grant(ctx, OperationRefresh, rreq.Provider, q)
+	if err != nil {
+		return Token{}, fmt.Errorf("%s refresh provider=%s: %w", login, rreq.Provider, err)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,144 @@
+package pocketbook_cloud_client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy describes how failed requests are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one. Values less than 2 disable retries.
+	MaxAttempts int
+	// MinBackoff is the delay before the second attempt, it doubles on every next attempt.
+	MinBackoff time.Duration
+	// MaxBackoff caps the exponential delay.
+	MaxBackoff time.Duration
+	// MaxRetryAfter is the longest Retry-After delay to wait, the request asking for a longer one is not retried.
+	// Zero means MaxBackoff.
+	MaxRetryAfter time.Duration
+	// RetryableStatus lists http status codes of responses to retry.
+	RetryableStatus []int
+	// RetryableError reports whether a transport error is retried. Nil means no transport error is retried.
+	RetryableError func(error) bool
+}
+
+// DefaultRetryPolicy returns the policy retrying transient server errors and transport errors up to 3 attempts.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		MinBackoff:  200 * time.Millisecond,
+		MaxBackoff:  5 * time.Second,
+		// Rate limited requests commonly ask for up to a minute.
+		MaxRetryAfter: time.Minute,
+		RetryableStatus: []int{
+			http.StatusRequestTimeout,
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		RetryableError: IsTransportError,
+	}
+}
+
+// IsTransportError reports whether the error is a failure to get a response, except for context cancellation.
+func IsTransportError(err error) bool {
+	return err != nil && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+func (p RetryPolicy) retryable(attempt int, rsp *http.Response, err error) bool {
+	if attempt >= p.MaxAttempts {
+		return false
+	}
+
+	if err != nil {
+		return p.RetryableError != nil && p.RetryableError(err)
+	}
+
+	return slices.Contains(p.RetryableStatus, rsp.StatusCode)
+}
+
+// backoff returns the delay before the next attempt.
+// The Retry-After header of the response takes precedence over the exponential backoff with jitter,
+// false is returned when it exceeds MaxRetryAfter.
+func (p RetryPolicy) backoff(attempt int, rsp *http.Response) (time.Duration, bool) {
+	if rsp != nil {
+		if d, ok := retryAfter(rsp.Header.Get("Retry-After")); ok {
+			limit := p.MaxRetryAfter
+			if limit <= 0 {
+				limit = p.MaxBackoff
+			}
+
+			return d, d <= limit
+		}
+	}
+
+	d := p.MinBackoff << (attempt - 1)
+	if d <= 0 || (p.MaxBackoff > 0 && d > p.MaxBackoff) {
+		d = p.MaxBackoff
+	}
+
+	if d <= 0 {
+		return 0, true
+	}
+
+	// equal jitter keeps at least a half of the delay
+	return d/2 + rand.N(d/2+1), true
+}
+
+// retryAfter parses the Retry-After header in seconds or http date.
+func retryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+
+	if sec, err := strconv.Atoi(v); err == nil && sec >= 0 {
+		return time.Duration(sec) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0), true
+	}
+
+	return 0, false
+}
+
+func replayable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// rewind returns the copy of the request for the next attempt with the body replayed.
+func rewind(req *http.Request) (*http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("replay request body: %w", err)
+	}
+
+	r := req.Clone(req.Context())
+	r.Body = body
+
+	return r, nil
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
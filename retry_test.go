@@ -0,0 +1,198 @@
+package pocketbook_cloud_client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	pbc "github.com/micronull/pocketbook-cloud-client"
+	"github.com/micronull/pocketbook-cloud-client/mocks"
+)
+
+func testRetryPolicy() pbc.RetryPolicy {
+	p := pbc.DefaultRetryPolicy()
+	p.MinBackoff = time.Millisecond
+	p.MaxBackoff = 5 * time.Millisecond
+
+	return p
+}
+
+func TestClient_Retry_StatusCode(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock), pbc.WithRetry(testRetryPolicy()))
+
+	isLogin := mock.MatchedBy(func(req *http.Request) bool {
+		return isAllTrue(
+			assert.Equal(t, "some.user.name", req.FormValue("username")),
+			assert.Equal(t, "some.password", req.FormValue("password")),
+		)
+	})
+
+	gomock.InOrder(
+		httpMock.EXPECT().
+			Do(isLogin).
+			Return(&http.Response{StatusCode: http.StatusBadGateway, Body: http.NoBody}, nil),
+		httpMock.EXPECT().
+			Do(isLogin).
+			Return(&http.Response{StatusCode: http.StatusOK, Body: must(testdata.Open("testdata/token.json"))}, nil),
+	)
+
+	got, err := client.Login(context.Background(), pbc.LoginRequest{UserName: "some.user.name", Password: "some.password"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "some.access.token", got.AccessToken)
+}
+
+func TestClient_Retry_TransportError(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock), pbc.WithRetry(testRetryPolicy()))
+
+	gomock.InOrder(
+		httpMock.EXPECT().
+			Do(gomock.Any()).
+			Return(nil, syscall.ECONNRESET),
+		httpMock.EXPECT().
+			Do(gomock.Any()).
+			Return(&http.Response{StatusCode: http.StatusOK, Body: must(testdata.Open("testdata/providers.json"))}, nil),
+	)
+
+	got, err := client.Providers(context.Background(), "some.user.name")
+	require.NoError(t, err)
+
+	assert.Len(t, got, 2)
+}
+
+func TestClient_Retry_MaxAttempts(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock), pbc.WithRetry(testRetryPolicy()))
+
+	httpMock.EXPECT().
+		Do(gomock.Any()).
+		Return(&http.Response{StatusCode: http.StatusServiceUnavailable, Body: http.NoBody}, nil).
+		Times(3)
+
+	_, err := client.Books(context.Background(), "some.token", 1, 0)
+	require.ErrorIs(t, err, pbc.ErrServer)
+}
+
+func TestClient_Retry_NotRetryable(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock), pbc.WithRetry(testRetryPolicy()))
+
+	httpMock.EXPECT().
+		Do(gomock.Any()).
+		Return(&http.Response{StatusCode: http.StatusNotFound, Body: http.NoBody}, nil)
+
+	_, err := client.Books(context.Background(), "some.token", 1, 0)
+	require.ErrorIs(t, err, pbc.ErrNotFound)
+}
+
+func TestClient_Retry_RetryAfter(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+
+	policy := testRetryPolicy()
+	policy.MinBackoff = time.Hour
+	policy.MaxBackoff = time.Hour
+
+	client := pbc.New(pbc.WithHTTPClient(httpMock), pbc.WithRetry(policy))
+
+	gomock.InOrder(
+		httpMock.EXPECT().
+			Do(gomock.Any()).
+			Return(&http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Header:     http.Header{"Retry-After": []string{"0"}},
+				Body:       http.NoBody,
+			}, nil),
+		httpMock.EXPECT().
+			Do(gomock.Any()).
+			Return(&http.Response{StatusCode: http.StatusOK, Body: must(testdata.Open("testdata/books.json"))}, nil),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := client.Books(ctx, "some.token", 1, 0)
+	require.NoError(t, err)
+}
+
+func TestClient_Retry_RetryAfterTooLong(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock), pbc.WithRetry(testRetryPolicy()))
+
+	httpMock.EXPECT().
+		Do(gomock.Any()).
+		Return(&http.Response{
+			StatusCode: http.StatusTooManyRequests,
+			Header:     http.Header{"Retry-After": []string{"86400"}},
+			Body:       http.NoBody,
+		}, nil)
+
+	start := time.Now()
+
+	_, err := client.Books(context.Background(), "some.token", 1, 0)
+	require.ErrorIs(t, err, pbc.ErrRateLimited)
+
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestClient_Retry_ContextCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+
+	policy := testRetryPolicy()
+	policy.MinBackoff = time.Hour
+	policy.MaxBackoff = time.Hour
+
+	client := pbc.New(pbc.WithHTTPClient(httpMock), pbc.WithRetry(policy))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	httpMock.EXPECT().
+		Do(gomock.Any()).
+		DoAndReturn(func(*http.Request) (*http.Response, error) {
+			time.AfterFunc(10*time.Millisecond, cancel)
+
+			return &http.Response{StatusCode: http.StatusBadGateway, Body: http.NoBody}, nil
+		})
+
+	_, err := client.Books(ctx, "some.token", 1, 0)
+	require.ErrorIs(t, err, context.Canceled)
+	require.ErrorIs(t, err, pbc.ErrServer)
+}
+
+func TestIsTransportError(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, pbc.IsTransportError(syscall.ECONNRESET))
+	assert.False(t, pbc.IsTransportError(context.Canceled))
+	assert.False(t, pbc.IsTransportError(errors.Join(errors.New("do"), context.DeadlineExceeded)))
+	assert.False(t, pbc.IsTransportError(nil))
+}
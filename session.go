@@ -0,0 +1,256 @@
+package pocketbook_cloud_client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// Session is a provider account bound to its token.
+// It exposes the account-scoped API without handling bearer tokens directly.
+type Session struct {
+	client   *Client
+	provider string
+	shopID   string
+	userName string
+	tokens   *TokenSource
+}
+
+// LoginSession logs in and returns the session of the account.
+// The login request is kept as the token source fallback, so the session re-logins when the token can not be refreshed.
+func (c Client) LoginSession(ctx context.Context, lreq LoginRequest, opts ...TokenSourceOption) (*Session, error) {
+	t, err := c.Login(ctx, lreq)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append([]TokenSourceOption{WithLoginFallback(lreq)}, opts...)
+
+	return NewSession(&c, lreq, t, opts...), nil
+}
+
+// StoredSession returns the session of the account with the token loaded from the store.
+// When the store has no token, it logs in and saves the token. Renewed tokens are written back to the store.
+func (c Client) StoredSession(ctx context.Context, store TokenStore, lreq LoginRequest, opts ...TokenSourceOption) (*Session, error) {
+	key := TokenKey{UserName: lreq.UserName, Provider: lreq.Provider}
+
+	t, err := store.Load(ctx, key)
+
+	switch {
+	case errors.Is(err, ErrTokenNotFound):
+		if t, err = c.Login(ctx, lreq); err != nil {
+			return nil, err
+		}
+
+		if err = store.Save(ctx, key, t); err != nil {
+			return nil, fmt.Errorf("save token: %w", err)
+		}
+	case err != nil:
+		return nil, fmt.Errorf("load token: %w", err)
+	}
+
+	fallback := []TokenSourceOption{WithTokenStore(store, key)}
+
+	if lreq.Password != "" {
+		fallback = append(fallback, WithLoginFallback(lreq))
+	}
+
+	return NewSession(&c, lreq, t, append(fallback, opts...)...), nil
+}
+
+// NewSession returns the session of the account for an already obtained token.
+// The password of the login request is not used unless WithLoginFallback is passed.
+func NewSession(client *Client, lreq LoginRequest, token Token, opts ...TokenSourceOption) *Session {
+	return &Session{
+		client:   client,
+		provider: lreq.Provider,
+		shopID:   lreq.ShopID,
+		userName: lreq.UserName,
+		tokens:   NewTokenSource(client, lreq.Provider, token, opts...),
+	}
+}
+
+// Provider returns the provider alias of the account.
+func (s *Session) Provider() string {
+	return s.provider
+}
+
+// ShopID returns the shop id of the provider.
+func (s *Session) ShopID() string {
+	return s.shopID
+}
+
+// UserName returns the user name of the account.
+func (s *Session) UserName() string {
+	return s.userName
+}
+
+// Token returns a valid token of the account, refreshing it if necessary.
+func (s *Session) Token(ctx context.Context) (Token, error) {
+	return s.tokens.Token(ctx)
+}
+
+// Books getting books of the account.
+func (s *Session) Books(ctx context.Context, limit, offset int) (Books, error) {
+	var books Books
+
+	err := s.authorized(ctx, func(token string) (err error) {
+		books, err = s.client.Books(ctx, token, limit, offset)
+
+		return err
+	})
+
+	return books, err
+}
+
+// StreamBooks gets the page of books of the account item by item, see Client.StreamBooks.
+func (s *Session) StreamBooks(ctx context.Context, limit, offset int, fn func(Book) error) (int, error) {
+	var total int
+
+	err := s.authorized(ctx, func(token string) (err error) {
+		total, err = s.client.StreamBooks(ctx, token, limit, offset, fn)
+
+		return err
+	})
+
+	return total, err
+}
+
+// AllBooks iterates over all books of the account, see Client.AllBooks.
+func (s *Session) AllBooks(ctx context.Context, pageSize int) iter.Seq2[Book, error] {
+	return allBooks(ctx, pageSize, s.Books)
+}
+
+// FetchAllBooks gets all books of the account requesting pages concurrently, see Client.FetchAllBooks.
+func (s *Session) FetchAllBooks(ctx context.Context, opts FetchOptions) (Books, error) {
+	return fetchAllBooks(ctx, opts, s.Books)
+}
+
+// authorized calls fn with a valid access token.
+// With WithReauth the token rejected as unauthorized is renewed and fn is called once again.
+func (s *Session) authorized(ctx context.Context, fn func(token string) error) error {
+	return s.authorize(ctx, true, fn)
+}
+
+// authorize calls fn with a valid access token, calling it once again with the renewed token only when it is replayable.
+func (s *Session) authorize(ctx context.Context, replayable bool, fn func(token string) error) error {
+	token, err := s.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = fn(token)
+	if err == nil || !replayable || s.tokens.reauth == nil || !errors.Is(err, ErrUnauthorized) {
+		return err
+	}
+
+	renewed, rerr := s.tokens.renewRejected(ctx, token)
+
+	s.tokens.reauth(ReauthEvent{Provider: s.provider, Cause: err, Err: rerr})
+
+	if rerr != nil {
+		return errors.Join(err, fmt.Errorf("session userName=%s provider=%s: %w", s.userName, s.provider, rerr))
+	}
+
+	return fn(renewed.AccessToken)
+}
+
+func (s *Session) accessToken(ctx context.Context) (string, error) {
+	token, err := s.tokens.AccessToken(ctx)
+	if err != nil {
+		return "", fmt.Errorf("session userName=%s provider=%s: %w", s.userName, s.provider, err)
+	}
+
+	return token, nil
+}
+
+// Download writes the file of the book to w, see Client.Download.
+func (s *Session) Download(ctx context.Context, book Book, w io.Writer, opts ...DownloadOption) error {
+	return s.authorized(ctx, func(token string) error {
+		return s.client.Download(ctx, token, book, w, opts...)
+	})
+}
+
+// DownloadToFile downloads the file of the book to the path, see Client.DownloadToFile.
+func (s *Session) DownloadToFile(ctx context.Context, book Book, path string, opts ...DownloadOption) error {
+	return s.authorized(ctx, func(token string) error {
+		return s.client.DownloadToFile(ctx, token, book, path, opts...)
+	})
+}
+
+// Cover downloads the cover of the book best fitting the spec, see Client.Cover.
+func (s *Session) Cover(ctx context.Context, book Book, spec CoverSpec) (*CoverImage, error) {
+	var img *CoverImage
+
+	err := s.authorized(ctx, func(token string) (err error) {
+		img, err = s.client.Cover(ctx, token, book, spec)
+
+		return err
+	})
+
+	return img, err
+}
+
+// Upload puts the file to the library of the account, see Client.Upload.
+// Only the upload of an io.ReadSeeker is repeated with the renewed token, other readers are drained by the rejected attempt.
+func (s *Session) Upload(ctx context.Context, name string, r io.Reader, size int64, opts UploadOptions) (Book, error) {
+	var book Book
+
+	_, replayable := r.(io.ReadSeeker)
+
+	err := s.authorize(ctx, replayable, func(token string) (err error) {
+		book, err = s.client.Upload(ctx, token, name, r, size, opts)
+
+		return err
+	})
+
+	return book, err
+}
+
+// UploadIfMissing uploads the local file unless the library of the account already has it, see Client.UploadIfMissing.
+func (s *Session) UploadIfMissing(ctx context.Context, filePath string, opts UploadIfMissingOptions) (UploadResult, error) {
+	var res UploadResult
+
+	err := s.authorized(ctx, func(token string) (err error) {
+		res, err = s.client.UploadIfMissing(ctx, token, filePath, opts)
+
+		return err
+	})
+
+	return res, err
+}
+
+// DeleteBook deletes the book from the library of the account, see Client.DeleteBook.
+func (s *Session) DeleteBook(ctx context.Context, book Book) error {
+	return s.authorized(ctx, func(token string) error {
+		return s.client.DeleteBook(ctx, token, book)
+	})
+}
+
+// RenameBook renames the book within its folder, see Client.RenameBook.
+func (s *Session) RenameBook(ctx context.Context, book Book, name string) (Book, error) {
+	var updated Book
+
+	err := s.authorized(ctx, func(token string) (err error) {
+		updated, err = s.client.RenameBook(ctx, token, book, name)
+
+		return err
+	})
+
+	return updated, err
+}
+
+// MoveBook moves the book to the folder, see Client.MoveBook.
+func (s *Session) MoveBook(ctx context.Context, book Book, folder string) (Book, error) {
+	var updated Book
+
+	err := s.authorized(ctx, func(token string) (err error) {
+		updated, err = s.client.MoveBook(ctx, token, book, folder)
+
+		return err
+	})
+
+	return updated, err
+}
@@ -0,0 +1,202 @@
+package pocketbook_cloud_client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	pbc "github.com/micronull/pocketbook-cloud-client"
+	"github.com/micronull/pocketbook-cloud-client/mocks"
+)
+
+func TestClient_LoginSession(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+
+	gomock.InOrder(
+		httpMock.EXPECT().
+			Do(mock.MatchedBy(func(req *http.Request) bool {
+				return isAllTrue(
+					assert.Equal(t, "/api/v1.0/auth/login/some_provider", req.URL.Path),
+					assert.Equal(t, "password", req.FormValue("grant_type")),
+				)
+			})).
+			Return(&http.Response{
+				StatusCode: http.StatusOK,
+				Body:       must(testdata.Open("testdata/token.json")),
+			}, nil),
+		httpMock.EXPECT().
+			Do(mock.MatchedBy(func(req *http.Request) bool {
+				return isAllTrue(
+					assert.Equal(t, "/api/v1.0/books", req.URL.Path),
+					assert.Equal(t, "limit=10&offset=5", req.URL.Query().Encode()),
+					assert.Equal(t, "Bearer some.access.token", req.Header.Get("Authorization")),
+				)
+			})).
+			Return(&http.Response{
+				StatusCode: http.StatusOK,
+				Body:       must(testdata.Open("testdata/books.json")),
+			}, nil),
+	)
+
+	req := pbc.LoginRequest{
+		ShopID:   "some.shop.id",
+		UserName: "some.user.name",
+		Password: "some.password",
+		Provider: "some_provider",
+	}
+
+	session, err := client.LoginSession(context.Background(), req)
+	require.NoError(t, err)
+
+	assert.Equal(t, "some_provider", session.Provider())
+	assert.Equal(t, "some.shop.id", session.ShopID())
+	assert.Equal(t, "some.user.name", session.UserName())
+
+	books, err := session.Books(context.Background(), 10, 5)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, books.Total)
+}
+
+func TestClient_LoginSession_Error(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+	errExpected := errors.New("something went wrong")
+
+	httpMock.EXPECT().
+		Do(gomock.Any()).
+		Return(nil, errExpected)
+
+	_, err := client.LoginSession(context.Background(), pbc.LoginRequest{})
+	require.ErrorIs(t, err, errExpected)
+}
+
+func TestClient_StoredSession(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+	store := pbc.NewFileTokenStore(filepath.Join(t.TempDir(), "tokens.json"))
+
+	httpMock.EXPECT().
+		Do(mock.MatchedBy(func(req *http.Request) bool {
+			return req.FormValue("grant_type") == "password"
+		})).
+		Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       must(testdata.Open("testdata/token.json")),
+		}, nil)
+
+	req := pbc.LoginRequest{
+		UserName: "some.user.name",
+		Password: "some.password",
+		Provider: "some_provider",
+	}
+
+	session, err := client.StoredSession(context.Background(), store, req)
+	require.NoError(t, err)
+
+	token, err := session.Token(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, "some.access.token", token.AccessToken)
+
+	// the second session is restored from the store without login
+	session, err = client.StoredSession(context.Background(), store, req)
+	require.NoError(t, err)
+
+	token, err = session.Token(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, "some.access.token", token.AccessToken)
+}
+
+func TestSession_Books_Reauth(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+
+	gomock.InOrder(
+		httpMock.EXPECT().
+			Do(mock.MatchedBy(func(req *http.Request) bool {
+				return req.Header.Get("Authorization") == "Bearer some.revoked.token"
+			})).
+			Return(&http.Response{StatusCode: http.StatusUnauthorized, Body: http.NoBody}, nil),
+		httpMock.EXPECT().
+			Do(mock.MatchedBy(func(req *http.Request) bool {
+				return isAllTrue(
+					assert.Equal(t, "refresh_token", req.FormValue("grant_type")),
+					assert.Equal(t, "some.refresh.token", req.FormValue("refresh_token")),
+				)
+			})).
+			Return(&http.Response{StatusCode: http.StatusOK, Body: must(testdata.Open("testdata/token_refreshed.json"))}, nil),
+		httpMock.EXPECT().
+			Do(mock.MatchedBy(func(req *http.Request) bool {
+				return req.Header.Get("Authorization") == "Bearer some.new.access.token"
+			})).
+			Return(&http.Response{StatusCode: http.StatusOK, Body: must(testdata.Open("testdata/books.json"))}, nil),
+	)
+
+	var events []pbc.ReauthEvent
+
+	token := pbc.Token{
+		AccessToken:  "some.revoked.token",
+		ExpiresIn:    time.Now().Add(time.Hour),
+		RefreshToken: "some.refresh.token",
+	}
+
+	session := pbc.NewSession(client, pbc.LoginRequest{Provider: "some_provider"}, token,
+		pbc.WithReauth(func(e pbc.ReauthEvent) { events = append(events, e) }),
+	)
+
+	books, err := session.Books(context.Background(), 1, 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, books.Total)
+
+	require.Len(t, events, 1)
+	assert.Equal(t, "some_provider", events[0].Provider)
+	require.ErrorIs(t, events[0].Cause, pbc.ErrUnauthorized)
+	require.NoError(t, events[0].Err)
+}
+
+func TestSession_Books_Unauthorized(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+
+	httpMock.EXPECT().
+		Do(gomock.Any()).
+		Return(&http.Response{StatusCode: http.StatusUnauthorized, Body: http.NoBody}, nil)
+
+	token := pbc.Token{
+		AccessToken:  "some.revoked.token",
+		ExpiresIn:    time.Now().Add(time.Hour),
+		RefreshToken: "some.refresh.token",
+	}
+
+	session := pbc.NewSession(client, pbc.LoginRequest{Provider: "some_provider"}, token)
+
+	_, err := session.Books(context.Background(), 1, 0)
+	require.ErrorIs(t, err, pbc.ErrUnauthorized)
+}
@@ -0,0 +1,122 @@
+package pocketbook_cloud_client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"slices"
+	"time"
+)
+
+// SnapshotVersion is the version of the snapshot format written by this release.
+const SnapshotVersion = 1
+
+// ErrSnapshotVersion is returned when the snapshot is written by a newer release.
+var ErrSnapshotVersion = errors.New("unsupported snapshot version")
+
+// LibrarySnapshot is the versioned envelope of the saved library.
+type LibrarySnapshot struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	Provider  string    `json:"provider,omitempty"`
+	UserName  string    `json:"user_name,omitempty"`
+	Books     Books     `json:"library"`
+}
+
+// snapshotMigrations upgrade the raw snapshot of the version to the next one.
+// A release changing the format bumps SnapshotVersion and registers the migration from the previous version.
+var snapshotMigrations = map[int]func(json.RawMessage) (json.RawMessage, error){}
+
+// WriteSnapshot writes the snapshot of the current version as indented JSON.
+// The access token is stripped from Book.Link and cover paths, the snapshot does not keep live tokens.
+func WriteSnapshot(w io.Writer, s LibrarySnapshot) error {
+	s.Version = SnapshotVersion
+	s.Books.Books = withoutAccessTokens(s.Books.Books)
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(s); err != nil {
+		return fmt.Errorf("encode snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// withoutAccessTokens returns the copy of the books with the access token stripped from their urls.
+func withoutAccessTokens(books []Book) []Book {
+	books = slices.Clone(books)
+
+	for i := range books {
+		books[i].Link = withoutAccessToken(books[i].Link)
+		books[i].MetaData.Cover = slices.Clone(books[i].MetaData.Cover)
+
+		for j := range books[i].MetaData.Cover {
+			books[i].MetaData.Cover[j].Path = withoutAccessToken(books[i].MetaData.Cover[j].Path)
+		}
+	}
+
+	return books
+}
+
+// withoutAccessToken removes the access_token parameter from the url, the url failed to parse is redacted.
+func withoutAccessToken(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return redactText(rawURL)
+	}
+
+	q := u.Query()
+	if !q.Has("access_token") {
+		return rawURL
+	}
+
+	q.Del("access_token")
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
+
+// ReadSnapshot reads the snapshot, migrating it from older versions.
+func ReadSnapshot(r io.Reader) (LibrarySnapshot, error) {
+	var raw json.RawMessage
+
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return LibrarySnapshot{}, fmt.Errorf("decode snapshot: %w", err)
+	}
+
+	for {
+		var header struct {
+			Version int `json:"version"`
+		}
+
+		if err := json.Unmarshal(raw, &header); err != nil {
+			return LibrarySnapshot{}, fmt.Errorf("decode snapshot version: %w", err)
+		}
+
+		if header.Version == SnapshotVersion {
+			break
+		}
+
+		migrate, ok := snapshotMigrations[header.Version]
+		if !ok || header.Version > SnapshotVersion {
+			return LibrarySnapshot{}, fmt.Errorf("%w: %d", ErrSnapshotVersion, header.Version)
+		}
+
+		var err error
+
+		if raw, err = migrate(raw); err != nil {
+			return LibrarySnapshot{}, fmt.Errorf("migrate snapshot version %d: %w", header.Version, err)
+		}
+	}
+
+	var s LibrarySnapshot
+
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return LibrarySnapshot{}, fmt.Errorf("decode snapshot: %w", err)
+	}
+
+	return s, nil
+}
@@ -0,0 +1,127 @@
+package pocketbook_cloud_client_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	pbc "github.com/micronull/pocketbook-cloud-client"
+	"github.com/micronull/pocketbook-cloud-client/mocks"
+)
+
+func TestBooks_JSON_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	books := fixtureBooks(t)
+
+	data, err := json.Marshal(books)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(data), `"read_position":{"pointer":null,`)
+	assert.Contains(t, string(data), `"metadata":{"title":"Война и мир","track_number":null,`)
+	assert.NotContains(t, string(data), `"ReadPosition"`)
+
+	var got pbc.Books
+
+	require.NoError(t, json.Unmarshal(data, &got))
+
+	assert.Equal(t, books, got)
+}
+
+func TestBook_JSON_RoundTrip_Missing(t *testing.T) {
+	t.Parallel()
+
+	book := pbc.Book{
+		ID: "some.id",
+		Position: pbc.BookPosition{
+			Pointer: pbc.Some("some.pointer"),
+			Page:    pbc.Null[string](),
+		},
+	}
+
+	data, err := json.Marshal(book)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(data), `"position":{"pointer":"some.pointer","page":null}`)
+
+	var got pbc.Book
+
+	require.NoError(t, json.Unmarshal(data, &got))
+
+	assert.Equal(t, book, got)
+}
+
+func TestSnapshot(t *testing.T) {
+	t.Parallel()
+
+	snapshot := pbc.LibrarySnapshot{
+		CreatedAt: time.Date(2024, time.December, 12, 0, 0, 0, 0, time.UTC),
+		Provider:  "some_provider",
+		UserName:  "some.user.name",
+		Books:     fixtureBooks(t),
+	}
+
+	buf := &bytes.Buffer{}
+
+	require.NoError(t, pbc.WriteSnapshot(buf, snapshot))
+	assert.True(t, strings.HasPrefix(buf.String(), "{\n  \"version\": 1,"))
+
+	assert.NotContains(t, buf.String(), "access_token")
+	assert.NotContains(t, buf.String(), "some.token")
+
+	got, err := pbc.ReadSnapshot(buf)
+	require.NoError(t, err)
+
+	// the books of the caller keep the token
+	assert.Contains(t, snapshot.Books.Books[0].Link, "access_token=some.token")
+
+	snapshot.Version = pbc.SnapshotVersion
+
+	for i := range snapshot.Books.Books {
+		book := &snapshot.Books.Books[i]
+		book.Link = strings.ReplaceAll(book.Link, "&access_token=some.token", "")
+
+		for j := range book.MetaData.Cover {
+			book.MetaData.Cover[j].Path = strings.ReplaceAll(book.MetaData.Cover[j].Path, "&access_token=some.token", "")
+		}
+	}
+
+	assert.Equal(t, snapshot, got)
+	assert.Equal(t, "https://cloud.pocketbook.digital/api/v1.0/files/voina-i-mir.epub?fast_hash=5c624ec0db399a8f1b99eddabf1e22c1",
+		got.Books.Books[0].Link)
+}
+
+func TestSnapshot_UnsupportedVersion(t *testing.T) {
+	t.Parallel()
+
+	_, err := pbc.ReadSnapshot(strings.NewReader(`{"version": 999, "library": {}}`))
+	require.ErrorIs(t, err, pbc.ErrSnapshotVersion)
+
+	_, err = pbc.ReadSnapshot(strings.NewReader(`{"library": {}}`))
+	require.ErrorIs(t, err, pbc.ErrSnapshotVersion)
+}
+
+func fixtureBooks(t *testing.T) pbc.Books {
+	t.Helper()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+
+	httpMock.EXPECT().
+		Do(gomock.Any()).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: must(testdata.Open("testdata/books.json"))}, nil)
+
+	books, err := client.Books(context.Background(), "some.token", 2, 0)
+	require.NoError(t, err)
+
+	return books
+}
@@ -0,0 +1,273 @@
+package pocketbook_cloud_client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// DefaultExpiryDelta is how long before Token.ExpiresIn the TokenSource refreshes the token.
+const DefaultExpiryDelta = time.Minute
+
+// DefaultRefreshTimeout limits the renewal of the token, including the login fallback.
+const DefaultRefreshTimeout = time.Minute
+
+// TokenSource hands out valid access tokens.
+// The token is refreshed shortly before it expires, falling back to the login request when the refresh fails.
+// It is safe for concurrent use, overlapping refreshes are collapsed into a single request.
+type TokenSource struct {
+	client   *Client
+	provider string
+	login    *LoginRequest
+	delta    time.Duration
+	timeout  time.Duration
+	store    TokenStore
+	storeKey TokenKey
+	saveErr  func(error)
+	reauth   func(ReauthEvent)
+
+	mu       sync.Mutex
+	token    Token
+	inflight *refreshCall
+}
+
+type refreshCall struct {
+	done  chan struct{}
+	token Token
+	err   error
+}
+
+type TokenSourceOption func(*TokenSource)
+
+// WithLoginFallback sets the login request used when the token cannot be refreshed.
+func WithLoginFallback(lreq LoginRequest) TokenSourceOption {
+	return func(ts *TokenSource) {
+		ts.login = &lreq
+	}
+}
+
+// WithExpiryDelta sets how long before expiration the token is refreshed.
+func WithExpiryDelta(d time.Duration) TokenSourceOption {
+	return func(ts *TokenSource) {
+		ts.delta = d
+	}
+}
+
+// WithRefreshTimeout sets how long the renewal of the token may take, so that a hung renewal does not block
+// all callers waiting for it.
+func WithRefreshTimeout(d time.Duration) TokenSourceOption {
+	return func(ts *TokenSource) {
+		ts.timeout = d
+	}
+}
+
+// WithTokenStore saves every renewed token to the store under the key.
+// The failure to save does not fail the request, the renewed token is used anyway,
+// the error is passed to the hook set by WithSaveError or logged by the client logger.
+func WithTokenStore(store TokenStore, key TokenKey) TokenSourceOption {
+	return func(ts *TokenSource) {
+		ts.store = store
+		ts.storeKey = key
+	}
+}
+
+// WithSaveError sets the hook called with the error of saving the renewed token to the store.
+func WithSaveError(hook func(error)) TokenSourceOption {
+	return func(ts *TokenSource) {
+		ts.saveErr = hook
+	}
+}
+
+// ReauthEvent describes the renewal of the token after the request was rejected as unauthorized.
+type ReauthEvent struct {
+	Provider string
+	// Cause is the unauthorized error of the rejected request.
+	Cause error
+	// Err is the renewal error, nil if the token was renewed and the request is retried.
+	Err error
+}
+
+// WithReauth makes the Session renew the token and retry the request once when it gets 401 Unauthorized.
+// The hook, if not nil, is called on every renewal.
+func WithReauth(hook func(ReauthEvent)) TokenSourceOption {
+	return func(ts *TokenSource) {
+		ts.reauth = func(e ReauthEvent) {
+			if hook != nil {
+				hook(e)
+			}
+		}
+	}
+}
+
+func NewTokenSource(client *Client, provider string, token Token, opts ...TokenSourceOption) *TokenSource {
+	ts := &TokenSource{
+		client:   client,
+		provider: provider,
+		token:    token,
+		delta:    DefaultExpiryDelta,
+		timeout:  DefaultRefreshTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(ts)
+	}
+
+	return ts
+}
+
+// Token returns a valid token, refreshing it if necessary.
+func (ts *TokenSource) Token(ctx context.Context) (Token, error) {
+	ts.mu.Lock()
+
+	if ts.valid(ts.token) {
+		t := ts.token
+		ts.mu.Unlock()
+
+		return t, nil
+	}
+
+	call := ts.startRefresh(ctx)
+
+	ts.mu.Unlock()
+
+	return call.wait(ctx)
+}
+
+// renewRejected renews the token rejected by the server,
+// unless it has already been replaced since it was handed out.
+func (ts *TokenSource) renewRejected(ctx context.Context, rejected string) (Token, error) {
+	ts.mu.Lock()
+
+	if ts.token.AccessToken != rejected && ts.valid(ts.token) {
+		t := ts.token
+		ts.mu.Unlock()
+
+		return t, nil
+	}
+
+	call := ts.startRefresh(ctx)
+
+	ts.mu.Unlock()
+
+	return call.wait(ctx)
+}
+
+// AccessToken returns a valid access token, refreshing it if necessary.
+func (ts *TokenSource) AccessToken(ctx context.Context) (string, error) {
+	t, err := ts.Token(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return t.AccessToken, nil
+}
+
+func (ts *TokenSource) valid(t Token) bool {
+	if t.AccessToken == "" {
+		return false
+	}
+
+	return t.ExpiresIn.IsZero() || time.Now().Add(ts.delta).Before(t.ExpiresIn)
+}
+
+func (call *refreshCall) wait(ctx context.Context) (Token, error) {
+	select {
+	case <-call.done:
+		return call.token, call.err
+	case <-ctx.Done():
+		return Token{}, ctx.Err()
+	}
+}
+
+// startRefresh returns the refresh in flight or starts a new one. Must be called with ts.mu held.
+// The refresh outlives the cancellation of ctx, so that other waiters still receive its result,
+// and is limited by the refresh timeout instead.
+func (ts *TokenSource) startRefresh(ctx context.Context) *refreshCall {
+	if ts.inflight != nil {
+		return ts.inflight
+	}
+
+	call := &refreshCall{done: make(chan struct{})}
+	ts.inflight = call
+	old := ts.token
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), ts.timeout)
+		defer cancel()
+
+		call.token, call.err = ts.renew(ctx, old)
+		if call.err == nil {
+			ts.save(ctx, call.token)
+		}
+
+		ts.mu.Lock()
+
+		if call.token.AccessToken != "" {
+			ts.token = call.token
+		}
+
+		ts.inflight = nil
+
+		ts.mu.Unlock()
+
+		close(call.done)
+	}()
+
+	return call
+}
+
+func (ts *TokenSource) renew(ctx context.Context, old Token) (Token, error) {
+	var errs []error
+
+	if old.RefreshToken != "" {
+		t, err := ts.client.Refresh(ctx, RefreshRequest{Provider: ts.provider, RefreshToken: old.RefreshToken})
+		if err == nil {
+			if t.RefreshToken == "" {
+				t.RefreshToken = old.RefreshToken
+			}
+
+			return t, nil
+		}
+
+		errs = append(errs, err)
+	}
+
+	if ts.login != nil {
+		t, err := ts.client.Login(ctx, *ts.login)
+		if err == nil {
+			return t, nil
+		}
+
+		errs = append(errs, err)
+	}
+
+	if len(errs) == 0 {
+		return Token{}, fmt.Errorf("renew token provider=%s: %w", ts.provider, ErrNoRenewal)
+	}
+
+	return Token{}, fmt.Errorf("renew token provider=%s: %w", ts.provider, errors.Join(errs...))
+}
+
+// save persists the renewed token. The token is in use even if it could not be saved.
+func (ts *TokenSource) save(ctx context.Context, t Token) {
+	if ts.store == nil {
+		return
+	}
+
+	err := ts.store.Save(ctx, ts.storeKey, t)
+	if err == nil {
+		return
+	}
+
+	err = fmt.Errorf("save renewed token provider=%s: %w", ts.provider, err)
+
+	switch {
+	case ts.saveErr != nil:
+		ts.saveErr(err)
+	case ts.client.logger != nil:
+		ts.client.logger.LogAttrs(ctx, slog.LevelWarn, "pocketbook token not saved", slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,246 @@
+package pocketbook_cloud_client_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	pbc "github.com/micronull/pocketbook-cloud-client"
+	"github.com/micronull/pocketbook-cloud-client/mocks"
+)
+
+func TestTokenSource_Token_Valid(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+
+	token := pbc.Token{
+		AccessToken:  "some.access.token",
+		TokenType:    pbc.TokenTypeBearer,
+		ExpiresIn:    time.Now().Add(time.Hour),
+		RefreshToken: "some.refresh.token",
+	}
+
+	ts := pbc.NewTokenSource(client, "some_provider", token)
+
+	got, err := ts.AccessToken(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, "some.access.token", got)
+}
+
+func TestTokenSource_Token_Refresh_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+
+	httpMock.EXPECT().
+		Do(mock.MatchedBy(func(req *http.Request) bool {
+			return isAllTrue(
+				assert.Equal(t, "refresh_token", req.FormValue("grant_type")),
+				assert.Equal(t, "some.refresh.token", req.FormValue("refresh_token")),
+			)
+		})).
+		DoAndReturn(func(*http.Request) (*http.Response, error) {
+			time.Sleep(10 * time.Millisecond)
+
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       must(testdata.Open("testdata/token_refreshed.json")),
+			}, nil
+		})
+
+	token := pbc.Token{
+		AccessToken:  "some.access.token",
+		ExpiresIn:    time.Now().Add(30 * time.Second), // inside DefaultExpiryDelta
+		RefreshToken: "some.refresh.token",
+	}
+
+	ts := pbc.NewTokenSource(client, "some_provider", token)
+
+	var wg sync.WaitGroup
+
+	for range 10 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			got, err := ts.AccessToken(context.Background())
+			if assert.NoError(t, err) {
+				assert.Equal(t, "some.new.access.token", got)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestTokenSource_Token_LoginFallback(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+
+	gomock.InOrder(
+		httpMock.EXPECT().
+			Do(mock.MatchedBy(func(req *http.Request) bool {
+				return req.FormValue("grant_type") == "refresh_token"
+			})).
+			Return(&http.Response{StatusCode: http.StatusUnauthorized, Body: http.NoBody}, nil),
+		httpMock.EXPECT().
+			Do(mock.MatchedBy(func(req *http.Request) bool {
+				return isAllTrue(
+					assert.Equal(t, "password", req.FormValue("grant_type")),
+					assert.Equal(t, "some.user.name", req.FormValue("username")),
+					assert.Equal(t, "some.password", req.FormValue("password")),
+				)
+			})).
+			Return(&http.Response{
+				StatusCode: http.StatusOK,
+				Body:       must(testdata.Open("testdata/token.json")),
+			}, nil),
+	)
+
+	token := pbc.Token{
+		AccessToken:  "some.expired.token",
+		ExpiresIn:    time.Now().Add(-time.Hour),
+		RefreshToken: "some.refresh.token",
+	}
+
+	lreq := pbc.LoginRequest{
+		UserName: "some.user.name",
+		Password: "some.password",
+		Provider: "some_provider",
+	}
+
+	ts := pbc.NewTokenSource(client, "some_provider", token, pbc.WithLoginFallback(lreq))
+
+	got, err := ts.Token(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, "some.access.token", got.AccessToken)
+	assert.Equal(t, "some.refresh.token", got.RefreshToken)
+}
+
+func TestTokenSource_Token_NoRenewal(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+
+	ts := pbc.NewTokenSource(client, "some_provider", pbc.Token{AccessToken: "some.expired.token", ExpiresIn: time.Now()})
+
+	_, err := ts.Token(context.Background())
+	require.ErrorIs(t, err, pbc.ErrNoRenewal)
+}
+
+func TestTokenSource_Token_RefreshTimeout(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	// the server never answers, the request is only ended by its context
+	blocking := pbc.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		calls.Add(1)
+		<-req.Context().Done()
+
+		return nil, req.Context().Err()
+	})
+
+	client := pbc.New(pbc.WithHTTPClient(blocking))
+	token := pbc.Token{AccessToken: "some.expired.token", ExpiresIn: time.Now(), RefreshToken: "some.refresh.token"}
+
+	ts := pbc.NewTokenSource(client, "some_provider", token, pbc.WithRefreshTimeout(50*time.Millisecond))
+
+	_, err := ts.Token(context.Background())
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// the timed out refresh is not in flight anymore, the next call starts a new one
+	_, err = ts.Token(context.Background())
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestTokenSource_Token_Store(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+
+	httpMock.EXPECT().
+		Do(gomock.Any()).
+		Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       must(testdata.Open("testdata/token_refreshed.json")),
+		}, nil)
+
+	store := pbc.NewFileTokenStore(filepath.Join(t.TempDir(), "tokens.json"))
+	key := pbc.TokenKey{UserName: "some.user.name", Provider: "some_provider"}
+	token := pbc.Token{AccessToken: "some.expired.token", ExpiresIn: time.Now(), RefreshToken: "some.refresh.token"}
+
+	ts := pbc.NewTokenSource(client, "some_provider", token, pbc.WithTokenStore(store, key))
+
+	_, err := ts.Token(context.Background())
+	require.NoError(t, err)
+
+	got, err := store.Load(context.Background(), key)
+	require.NoError(t, err)
+
+	assert.Equal(t, "some.new.access.token", got.AccessToken)
+	assert.Equal(t, "some.new.refresh.token", got.RefreshToken)
+}
+
+func TestTokenSource_Token_StoreError(t *testing.T) {
+	t.Parallel()
+
+	ctrlMock := gomock.NewController(t)
+	httpMock := mocks.NewMockDoer(ctrlMock)
+	client := pbc.New(pbc.WithHTTPClient(httpMock))
+
+	httpMock.EXPECT().
+		Do(gomock.Any()).
+		Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       must(testdata.Open("testdata/token_refreshed.json")),
+		}, nil)
+
+	// The parent of the store is a regular file, so the token can not be saved.
+	parent := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(parent, nil, 0o600))
+
+	store := pbc.NewFileTokenStore(filepath.Join(parent, "tokens.json"))
+	key := pbc.TokenKey{UserName: "some.user.name", Provider: "some_provider"}
+	token := pbc.Token{AccessToken: "some.expired.token", ExpiresIn: time.Now(), RefreshToken: "some.refresh.token"}
+
+	var saveErr error
+
+	ts := pbc.NewTokenSource(client, "some_provider", token,
+		pbc.WithTokenStore(store, key),
+		pbc.WithSaveError(func(err error) { saveErr = err }),
+	)
+
+	got, err := ts.Token(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, "some.new.access.token", got.AccessToken)
+	assert.Error(t, saveErr)
+}
@@ -0,0 +1,391 @@
+package pocketbook_cloud_client
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ErrTokenNotFound is returned by a TokenStore when there is no token for the key.
+var ErrTokenNotFound = errors.New("token not found")
+
+// TokenKey identifies a token of the account in a TokenStore.
+type TokenKey struct {
+	UserName string `json:"user_name"`
+	Provider string `json:"provider"`
+}
+
+// TokenStore persists tokens between runs.
+type TokenStore interface {
+	// Load returns the token of the key or ErrTokenNotFound.
+	Load(ctx context.Context, key TokenKey) (Token, error)
+	// Save stores the token of the key, replacing the previous one.
+	Save(ctx context.Context, key TokenKey, token Token) error
+	// Delete removes the token of the key. Deleting a missing token is not an error.
+	Delete(ctx context.Context, key TokenKey) error
+}
+
+// FileTokenStore keeps tokens in a JSON file readable only by the owner.
+type FileTokenStore struct {
+	path  string
+	codec tokenFileCodec
+	mu    sync.Mutex
+}
+
+// NewFileTokenStore returns the store of a plain JSON file.
+func NewFileTokenStore(path string) *FileTokenStore {
+	return &FileTokenStore{path: path, codec: plainCodec{}}
+}
+
+// NewEncryptedFileTokenStore returns the store of a file encrypted by AES-GCM
+// with the key derived from the passphrase by PBKDF2-HMAC-SHA256.
+func NewEncryptedFileTokenStore(path, passphrase string) *FileTokenStore {
+	return &FileTokenStore{path: path, codec: &aesCodec{passphrase: []byte(passphrase)}}
+}
+
+func (s *FileTokenStore) Load(_ context.Context, key TokenKey) (Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.read()
+	if err != nil {
+		return Token{}, err
+	}
+
+	for _, r := range records {
+		if r.key() == key {
+			return r.token(), nil
+		}
+	}
+
+	return Token{}, fmt.Errorf("load userName=%s provider=%s: %w", key.UserName, key.Provider, ErrTokenNotFound)
+}
+
+func (s *FileTokenStore) Save(_ context.Context, key TokenKey, token Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	rec := newTokenRecord(key, token)
+	found := false
+
+	for i, r := range records {
+		if r.key() == key {
+			records[i] = rec
+			found = true
+
+			break
+		}
+	}
+
+	if !found {
+		records = append(records, rec)
+	}
+
+	return s.write(records)
+}
+
+func (s *FileTokenStore) Delete(_ context.Context, key TokenKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	kept := records[:0]
+
+	for _, r := range records {
+		if r.key() != key {
+			kept = append(kept, r)
+		}
+	}
+
+	if len(kept) == len(records) {
+		return nil
+	}
+
+	return s.write(kept)
+}
+
+func (s *FileTokenStore) read() ([]tokenRecord, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("read token store: %w", err)
+	}
+
+	plain, err := s.codec.decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("decode token store: %w", err)
+	}
+
+	var records []tokenRecord
+
+	if err = json.Unmarshal(plain, &records); err != nil {
+		return nil, fmt.Errorf("unmarshal token store: %w", err)
+	}
+
+	return records, nil
+}
+
+// write replaces the file atomically, so a crash never leaves a truncated store.
+func (s *FileTokenStore) write(records []tokenRecord) error {
+	plain, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal token store: %w", err)
+	}
+
+	data, err := s.codec.encode(plain)
+	if err != nil {
+		return fmt.Errorf("encode token store: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create token store: %w", err)
+	}
+
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if err = tmp.Chmod(0o600); err != nil {
+		_ = tmp.Close()
+
+		return fmt.Errorf("chmod token store: %w", err)
+	}
+
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+
+		return fmt.Errorf("write token store: %w", err)
+	}
+
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("close token store: %w", err)
+	}
+
+	if err = os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("rename token store: %w", err)
+	}
+
+	return nil
+}
+
+type tokenRecord struct {
+	UserName     string    `json:"user_name"`
+	Provider     string    `json:"provider"`
+	AccessToken  string    `json:"access_token"`
+	TokenType    tokenType `json:"token_type"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	RefreshToken string    `json:"refresh_token"`
+}
+
+// UnmarshalJSON also reads the expiration time of the files written under the "expires_in" key.
+func (r *tokenRecord) UnmarshalJSON(data []byte) error {
+	type record tokenRecord
+
+	var v struct {
+		record
+		ExpiresIn time.Time `json:"expires_in"`
+	}
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*r = tokenRecord(v.record)
+
+	if r.ExpiresAt.IsZero() {
+		r.ExpiresAt = v.ExpiresIn
+	}
+
+	return nil
+}
+
+func newTokenRecord(key TokenKey, t Token) tokenRecord {
+	return tokenRecord{
+		UserName:     key.UserName,
+		Provider:     key.Provider,
+		AccessToken:  t.AccessToken,
+		TokenType:    t.TokenType,
+		ExpiresAt:    t.ExpiresIn,
+		RefreshToken: t.RefreshToken,
+	}
+}
+
+func (r tokenRecord) key() TokenKey {
+	return TokenKey{UserName: r.UserName, Provider: r.Provider}
+}
+
+func (r tokenRecord) token() Token {
+	return Token{
+		AccessToken:  r.AccessToken,
+		TokenType:    r.TokenType,
+		ExpiresIn:    r.ExpiresAt,
+		RefreshToken: r.RefreshToken,
+	}
+}
+
+type tokenFileCodec interface {
+	encode(plain []byte) ([]byte, error)
+	decode(data []byte) ([]byte, error)
+}
+
+type plainCodec struct{}
+
+func (plainCodec) encode(plain []byte) ([]byte, error) { return plain, nil }
+
+func (plainCodec) decode(data []byte) ([]byte, error) { return data, nil }
+
+const (
+	aesCodecMagic      = "PBTS1"
+	aesCodecSaltSize   = 16
+	aesCodecIterations = 600_000
+)
+
+// aesCodec seals the file as magic | salt | nonce | ciphertext.
+// The derived key is cached per salt, because the derivation is deliberately slow.
+type aesCodec struct {
+	passphrase []byte
+	salt       []byte
+	key        []byte
+}
+
+func (c *aesCodec) encode(plain []byte) ([]byte, error) {
+	if c.salt == nil {
+		salt := make([]byte, aesCodecSaltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, fmt.Errorf("generate salt: %w", err)
+		}
+
+		c.salt = salt
+		c.key = nil
+	}
+
+	aead, err := c.aead(c.salt)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+
+	out := make([]byte, 0, len(aesCodecMagic)+len(c.salt)+len(nonce)+len(plain)+aead.Overhead())
+	out = append(out, aesCodecMagic...)
+	out = append(out, c.salt...)
+	out = append(out, nonce...)
+
+	return aead.Seal(out, nonce, plain, []byte(aesCodecMagic)), nil
+}
+
+func (c *aesCodec) decode(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, []byte(aesCodecMagic)) {
+		return nil, errors.New("not an encrypted token store")
+	}
+
+	data = data[len(aesCodecMagic):]
+
+	if len(data) < aesCodecSaltSize {
+		return nil, errors.New("truncated encrypted token store")
+	}
+
+	salt, data := data[:aesCodecSaltSize], data[aesCodecSaltSize:]
+
+	aead, err := c.aead(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("truncated encrypted token store")
+	}
+
+	nonce, data := data[:aead.NonceSize()], data[aead.NonceSize():]
+
+	plain, err := aead.Open(nil, nonce, data, []byte(aesCodecMagic))
+	if err != nil {
+		return nil, fmt.Errorf("decrypt: %w", err)
+	}
+
+	c.salt = bytes.Clone(salt)
+
+	return plain, nil
+}
+
+func (c *aesCodec) aead(salt []byte) (cipher.AEAD, error) {
+	if c.key == nil || !bytes.Equal(c.salt, salt) {
+		c.key = pbkdf2SHA256(c.passphrase, salt, aesCodecIterations, 32)
+		c.salt = bytes.Clone(salt)
+	}
+
+	block, err := aes.NewCipher(c.key)
+	if err != nil {
+		return nil, fmt.Errorf("new cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("new gcm: %w", err)
+	}
+
+	return aead, nil
+}
+
+// pbkdf2SHA256 derives the key by RFC 8018 PBKDF2 with HMAC-SHA256.
+func pbkdf2SHA256(password, salt []byte, iter, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	hashLen := prf.Size()
+	blocks := (keyLen + hashLen - 1) / hashLen
+	key := make([]byte, 0, blocks*hashLen)
+	u := make([]byte, hashLen)
+	t := make([]byte, hashLen)
+
+	var idx [4]byte
+
+	for block := 1; block <= blocks; block++ {
+		binary.BigEndian.PutUint32(idx[:], uint32(block))
+
+		prf.Reset()
+		prf.Write(salt)
+		prf.Write(idx[:])
+		u = prf.Sum(u[:0])
+		copy(t, u)
+
+		for n := 1; n < iter; n++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+
+			for i := range t {
+				t[i] ^= u[i]
+			}
+		}
+
+		key = append(key, t...)
+	}
+
+	return key[:keyLen]
+}
@@ -0,0 +1,165 @@
+package pocketbook_cloud_client_test
+
+import (
+	"context"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pbc "github.com/micronull/pocketbook-cloud-client"
+)
+
+func TestFileTokenStore(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "tokens.json")
+
+	testTokenStore(t, pbc.NewFileTokenStore(path), path)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(data), "some.other.access.token")
+}
+
+func TestFileTokenStore_ExpiresIn(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	expires := time.Date(2024, time.December, 12, 15, 0, 0, 0, time.UTC)
+
+	// the file written before the expiration time was stored under "expires_at"
+	legacy := `[{"user_name":"some.user.name","provider":"some_provider","access_token":"some.access.token",` +
+		`"token_type":"Bearer","expires_in":"2024-12-12T15:00:00Z","refresh_token":"some.refresh.token"}]`
+	require.NoError(t, os.WriteFile(path, []byte(legacy), 0o600))
+
+	store := pbc.NewFileTokenStore(path)
+	key := pbc.TokenKey{UserName: "some.user.name", Provider: "some_provider"}
+
+	got, err := store.Load(context.Background(), key)
+	require.NoError(t, err)
+
+	assert.Equal(t, expires, got.ExpiresIn)
+
+	require.NoError(t, store.Save(context.Background(), key, got))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(data), `"expires_at": "2024-12-12T15:00:00Z"`)
+	assert.NotContains(t, string(data), "expires_in")
+}
+
+func TestEncryptedFileTokenStore(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "tokens.enc")
+
+	testTokenStore(t, pbc.NewEncryptedFileTokenStore(path, "some.passphrase"), path)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	assert.NotContains(t, string(data), "some.other.access.token")
+
+	got, err := pbc.NewEncryptedFileTokenStore(path, "some.passphrase").
+		Load(context.Background(), pbc.TokenKey{UserName: "some.user.name", Provider: "some_other_provider"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "some.other.access.token", got.AccessToken)
+
+	_, err = pbc.NewEncryptedFileTokenStore(path, "wrong.passphrase").
+		Load(context.Background(), pbc.TokenKey{UserName: "some.user.name", Provider: "some_other_provider"})
+	require.ErrorContains(t, err, "decrypt")
+}
+
+func testTokenStore(t *testing.T, store pbc.TokenStore, path string) {
+	t.Helper()
+
+	ctx := context.Background()
+	key := pbc.TokenKey{UserName: "some.user.name", Provider: "some_provider"}
+	otherKey := pbc.TokenKey{UserName: "some.user.name", Provider: "some_other_provider"}
+
+	_, err := store.Load(ctx, key)
+	require.ErrorIs(t, err, pbc.ErrTokenNotFound)
+
+	token := pbc.Token{
+		AccessToken:  "some.access.token",
+		TokenType:    pbc.TokenTypeBearer,
+		ExpiresIn:    time.Date(2024, time.December, 11, 15, 41, 28, 0, time.UTC),
+		RefreshToken: "some.refresh.token",
+	}
+
+	require.NoError(t, store.Save(ctx, key, token))
+	require.NoError(t, store.Save(ctx, otherKey, pbc.Token{AccessToken: "some.other.access.token"}))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	got, err := store.Load(ctx, key)
+	require.NoError(t, err)
+
+	assert.Equal(t, token.AccessToken, got.AccessToken)
+	assert.Equal(t, token.TokenType, got.TokenType)
+	assert.True(t, token.ExpiresIn.Equal(got.ExpiresIn))
+	assert.Equal(t, token.RefreshToken, got.RefreshToken)
+
+	token.AccessToken = "some.new.access.token"
+
+	require.NoError(t, store.Save(ctx, key, token))
+
+	got, err = store.Load(ctx, key)
+	require.NoError(t, err)
+
+	assert.Equal(t, "some.new.access.token", got.AccessToken)
+
+	require.NoError(t, store.Delete(ctx, key))
+	require.NoError(t, store.Delete(ctx, key))
+
+	_, err = store.Load(ctx, key)
+	require.ErrorIs(t, err, pbc.ErrTokenNotFound)
+
+	got, err = store.Load(ctx, otherKey)
+	require.NoError(t, err)
+
+	assert.Equal(t, "some.other.access.token", got.AccessToken)
+}
+
+func TestPBKDF2SHA256(t *testing.T) {
+	t.Parallel()
+
+	// Test vectors of RFC 7914, section 11.
+	tests := []struct {
+		password, salt string
+		iter           int
+		want           string
+	}{
+		{
+			password: "passwd", salt: "salt", iter: 1,
+			want: "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc" +
+				"49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783",
+		},
+		{
+			password: "Password", salt: "NaCl", iter: 80000,
+			want: "4ddcd8f60b98be21830cee5ef22701f9641a4418d04c0414aeff08876b34ab56" +
+				"a1d425a1225833549adb841b51c9b3176a272bdebba1d078478f62b397f33c8d",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.password, func(t *testing.T) {
+			t.Parallel()
+
+			got := pbc.PBKDF2SHA256([]byte(tt.password), []byte(tt.salt), tt.iter, 64)
+
+			assert.Equal(t, tt.want, hex.EncodeToString(got))
+		})
+	}
+}
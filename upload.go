@@ -0,0 +1,136 @@
+package pocketbook_cloud_client
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// UploadOptions configures Upload.
+type UploadOptions struct {
+	// Path is the folder of the book in the library, the root by default.
+	Path string
+	// ClientMtime is the modification time of the local file, not sent when zero.
+	ClientMtime time.Time
+	// Format overrides the format detected by DetectFormat.
+	Format Format
+}
+
+// Upload puts the file to the library under the name, replacing the file of the same name, and returns the book.
+// The size is the length of r, -1 when it is unknown.
+// The format is detected from the extension of the name and the magic bytes of the file.
+// Only the upload of an io.ReadSeeker is retried, it is rewound to the start when the upload fails.
+func (c Client) Upload(ctx context.Context, token, name string, r io.Reader, size int64, opts UploadOptions) (Book, error) {
+	book, err := c.upload(ctx, token, name, r, size, opts)
+	if err != nil {
+		return Book{}, fmt.Errorf("upload %s: %w", name, err)
+	}
+
+	return book, nil
+}
+
+func (c Client) upload(ctx context.Context, token, name string, r io.Reader, size int64, opts UploadOptions) (Book, error) {
+	head, body, getBody, err := uploadBody(r)
+	if err != nil {
+		return Book{}, err
+	}
+
+	format := opts.Format
+	if format == "" {
+		format = DetectFormat(name, head)
+	}
+
+	contentType := string(format.MimeType())
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	u := c.url(files).JoinPath(opts.Path, name)
+
+	if !opts.ClientMtime.IsZero() {
+		q := u.Query()
+		q.Set("client_mtime", opts.ClientMtime.UTC().Format(time.RFC3339))
+		u.RawQuery = q.Encode()
+	}
+
+	req := &http.Request{
+		Method:        http.MethodPut,
+		URL:           u,
+		Body:          body,
+		GetBody:       getBody,
+		ContentLength: size,
+		Header: http.Header{
+			"Authorization": []string{string(TokenTypeBearer) + " " + token},
+			"Content-Type":  []string{contentType},
+		},
+	}
+
+	if size == 0 {
+		req.Body = http.NoBody
+	}
+
+	respBody, err := c.req(OperationUpload, req.WithContext(ctx))
+	if err != nil {
+		// Rewind the file, so the caller can retry the upload.
+		if getBody != nil {
+			_, _ = getBody()
+		}
+
+		return Book{}, err
+	}
+
+	var item bookItem
+
+	if err = c.decode(OperationUpload, respBody, &item); err != nil {
+		return Book{}, err
+	}
+
+	return mappingBook(item), nil
+}
+
+// uploadBody returns the head of the file for the format detection and the request body.
+// The body of io.ReadSeeker is replayable by getBody seeking to the start of the file.
+func uploadBody(r io.Reader) (head []byte, body io.ReadCloser, getBody func() (io.ReadCloser, error), err error) {
+	rs, ok := r.(io.ReadSeeker)
+	if !ok {
+		br := bufio.NewReaderSize(r, DetectHeadSize)
+
+		head, err = br.Peek(DetectHeadSize)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, nil, nil, fmt.Errorf("read file: %w", err)
+		}
+
+		return head, io.NopCloser(br), nil, nil
+	}
+
+	start, err := rs.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("seek file: %w", err)
+	}
+
+	head = make([]byte, DetectHeadSize)
+
+	n, err := io.ReadFull(rs, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, nil, nil, fmt.Errorf("read file: %w", err)
+	}
+
+	getBody = func() (io.ReadCloser, error) {
+		if _, err := rs.Seek(start, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("seek file: %w", err)
+		}
+
+		return io.NopCloser(rs), nil
+	}
+
+	body, err = getBody()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return head[:n], body, getBody, nil
+}
@@ -0,0 +1,169 @@
+package pocketbook_cloud_client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ConflictPolicy is what UploadIfMissing does when the library has a different file under the same name.
+type ConflictPolicy int
+
+const (
+	// ConflictSkip keeps the file of the library.
+	ConflictSkip ConflictPolicy = iota
+	// ConflictReplace uploads the file over the one of the library.
+	ConflictReplace
+	// ConflictRename uploads the file under a free name, e.g. "book (1).epub".
+	ConflictRename
+)
+
+// UploadAction is what UploadIfMissing has done.
+type UploadAction string
+
+const (
+	// UploadActionExists means the library already has the same file, nothing is uploaded.
+	UploadActionExists UploadAction = "exists"
+	// UploadActionSkipped means the name is taken by a different file and the conflict policy is ConflictSkip.
+	UploadActionSkipped UploadAction = "skipped"
+	// UploadActionUploaded means the file is new to the library.
+	UploadActionUploaded UploadAction = "uploaded"
+	// UploadActionReplaced means the file is uploaded over a different file of the same name.
+	UploadActionReplaced UploadAction = "replaced"
+	// UploadActionRenamed means the file is uploaded under a free name.
+	UploadActionRenamed UploadAction = "renamed"
+)
+
+// UploadIfMissingOptions configures UploadIfMissing.
+type UploadIfMissingOptions struct {
+	UploadOptions
+	// Name is the name of the book in the library, the base name of the local file by default.
+	Name string
+	// Library is the books to check the file against, e.g. LibrarySnapshot.Books.Books.
+	// The library is fetched with FetchAllBooks when it is nil.
+	Library  []Book
+	Conflict ConflictPolicy
+}
+
+// UploadResult is the outcome of UploadIfMissing.
+type UploadResult struct {
+	Action UploadAction
+	// Book is the uploaded book, or the book of the library for UploadActionExists and UploadActionSkipped.
+	Book Book
+}
+
+// UploadIfMissing uploads the local file unless the library already has it.
+// The file is looked up by FastHash, size and Md5Hash, computed locally.
+// A different file under the same name is resolved according to the conflict policy.
+// The client mtime defaults to the modification time of the local file.
+func (c Client) UploadIfMissing(ctx context.Context, token, filePath string, opts UploadIfMissingOptions) (UploadResult, error) {
+	res, err := c.uploadIfMissing(ctx, token, filePath, opts)
+	if err != nil {
+		return UploadResult{}, fmt.Errorf("upload %s if missing: %w", filePath, err)
+	}
+
+	return res, nil
+}
+
+func (c Client) uploadIfMissing(ctx context.Context, token, filePath string, opts UploadIfMissingOptions) (UploadResult, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return UploadResult{}, fmt.Errorf("open file: %w", err)
+	}
+
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return UploadResult{}, fmt.Errorf("stat file: %w", err)
+	}
+
+	fastHash, err := FastHash(f)
+	if err != nil {
+		return UploadResult{}, err
+	}
+
+	md5Hash, err := Md5Hash(f)
+	if err != nil {
+		return UploadResult{}, err
+	}
+
+	if _, err = f.Seek(0, io.SeekStart); err != nil {
+		return UploadResult{}, fmt.Errorf("seek file: %w", err)
+	}
+
+	library := opts.Library
+	if library == nil {
+		books, err := c.FetchAllBooks(ctx, token, FetchOptions{})
+		if err != nil {
+			return UploadResult{}, err
+		}
+
+		library = books.Books
+	}
+
+	for _, b := range library {
+		if b.FastHash == fastHash && int64(b.Bytes) == info.Size() && (b.Md5Hash == "" || b.Md5Hash == md5Hash) {
+			return UploadResult{Action: UploadActionExists, Book: b}, nil
+		}
+	}
+
+	name := opts.Name
+	if name == "" {
+		name = filepath.Base(filePath)
+	}
+
+	if opts.ClientMtime.IsZero() {
+		opts.ClientMtime = info.ModTime()
+	}
+
+	taken := make(map[string]Book, len(library))
+	for _, b := range library {
+		taken[libraryPath(b.Path)] = b
+	}
+
+	action := UploadActionUploaded
+
+	if existing, ok := taken[libraryPath(path.Join(opts.Path, name))]; ok {
+		switch opts.Conflict {
+		case ConflictSkip:
+			return UploadResult{Action: UploadActionSkipped, Book: existing}, nil
+		case ConflictReplace:
+			action = UploadActionReplaced
+		case ConflictRename:
+			action = UploadActionRenamed
+			name = freeName(taken, opts.Path, name)
+		}
+	}
+
+	book, err := c.Upload(ctx, token, name, f, info.Size(), opts.UploadOptions)
+	if err != nil {
+		return UploadResult{}, err
+	}
+
+	return UploadResult{Action: action, Book: book}, nil
+}
+
+// libraryPath is the path of the book in the library in the canonical form, e.g. "/folder/book.epub".
+func libraryPath(p string) string {
+	return path.Clean("/" + p)
+}
+
+// freeName returns the name with the smallest number suffix not taken in the folder, e.g. "book (1).epub".
+func freeName(taken map[string]Book, folder, name string) string {
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+
+	for i := 1; ; i++ {
+		candidate := base + " (" + strconv.Itoa(i) + ")" + ext
+
+		if _, ok := taken[libraryPath(path.Join(folder, candidate))]; !ok {
+			return candidate
+		}
+	}
+}
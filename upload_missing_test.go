@@ -0,0 +1,118 @@
+package pocketbook_cloud_client_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pbc "github.com/micronull/pocketbook-cloud-client"
+)
+
+func TestClient_UploadIfMissing(t *testing.T) {
+	t.Parallel()
+
+	local := epubFile()
+	other := append(epubFile(), "other edition"...)
+
+	tests := []struct {
+		name     string
+		library  map[string][]byte
+		conflict pbc.ConflictPolicy
+		action   pbc.UploadAction
+		path     string
+		stored   map[string][]byte
+	}{
+		{
+			name:   "missing",
+			action: pbc.UploadActionUploaded,
+			path:   "/voina-i-mir.epub",
+			stored: map[string][]byte{"/voina-i-mir.epub": local},
+		},
+		{
+			name:    "exists under another name",
+			library: map[string][]byte{"/classic/war-and-peace.epub": local},
+			action:  pbc.UploadActionExists,
+			path:    "/classic/war-and-peace.epub",
+			stored:  map[string][]byte{"/classic/war-and-peace.epub": local},
+		},
+		{
+			name:     "conflict skip",
+			library:  map[string][]byte{"/voina-i-mir.epub": other},
+			conflict: pbc.ConflictSkip,
+			action:   pbc.UploadActionSkipped,
+			path:     "/voina-i-mir.epub",
+			stored:   map[string][]byte{"/voina-i-mir.epub": other},
+		},
+		{
+			name:     "conflict replace",
+			library:  map[string][]byte{"/voina-i-mir.epub": other},
+			conflict: pbc.ConflictReplace,
+			action:   pbc.UploadActionReplaced,
+			path:     "/voina-i-mir.epub",
+			stored:   map[string][]byte{"/voina-i-mir.epub": local},
+		},
+		{
+			name:     "conflict rename",
+			library:  map[string][]byte{"/voina-i-mir.epub": other, "/voina-i-mir (1).epub": other[:100]},
+			conflict: pbc.ConflictRename,
+			action:   pbc.UploadActionRenamed,
+			path:     "/voina-i-mir (2).epub",
+			stored: map[string][]byte{
+				"/voina-i-mir.epub":     other,
+				"/voina-i-mir (1).epub": other[:100],
+				"/voina-i-mir (2).epub": local,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs, client := newFileServer(t)
+			for name, body := range tt.library {
+				fs.put(name, body, string(pbc.MimeTypeEPUB), "")
+			}
+
+			file := filepath.Join(t.TempDir(), "voina-i-mir.epub")
+			require.NoError(t, os.WriteFile(file, local, 0o644))
+
+			res, err := client.UploadIfMissing(context.Background(), "some.token", file, pbc.UploadIfMissingOptions{
+				Conflict: tt.conflict,
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.action, res.Action)
+			assert.Equal(t, tt.path, res.Book.Path)
+
+			for name, body := range tt.stored {
+				assert.Equal(t, body, fs.body(name), name)
+			}
+		})
+	}
+}
+
+func TestClient_UploadIfMissing_Snapshot(t *testing.T) {
+	t.Parallel()
+
+	fs, client := newFileServer(t)
+	local := epubFile()
+
+	file := filepath.Join(t.TempDir(), "voina-i-mir.epub")
+	require.NoError(t, os.WriteFile(file, local, 0o644))
+
+	fastHash := must(pbc.FastHash(bytes.NewReader(local)))
+	library := []pbc.Book{{ID: "76220203", Path: "/voina-i-mir.epub", Bytes: len(local), FastHash: fastHash}}
+
+	res, err := client.UploadIfMissing(context.Background(), "some.token", file, pbc.UploadIfMissingOptions{Library: library})
+	require.NoError(t, err)
+
+	assert.Equal(t, pbc.UploadActionExists, res.Action)
+	assert.Equal(t, library[0], res.Book)
+	assert.Nil(t, fs.body("/voina-i-mir.epub"))
+}
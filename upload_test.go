@@ -0,0 +1,103 @@
+package pocketbook_cloud_client_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pbc "github.com/micronull/pocketbook-cloud-client"
+)
+
+func epubFile() []byte {
+	head := "PK\x03\x04" + strings.Repeat("\x00", 26) + "mimetypeapplication/epub+zip"
+
+	return []byte(head + strings.Repeat("content", 100))
+}
+
+func TestClient_Upload(t *testing.T) {
+	t.Parallel()
+
+	fs, client := newFileServer(t)
+	file := epubFile()
+	mtime := time.Date(2024, time.December, 10, 15, 41, 28, 0, time.UTC)
+
+	book, err := client.Upload(context.Background(), "some.token", "voina-i-mir.epub",
+		bytes.NewReader(file), int64(len(file)), pbc.UploadOptions{Path: "/classic", ClientMtime: mtime})
+	require.NoError(t, err)
+
+	assert.Equal(t, file, fs.body("/classic/voina-i-mir.epub"))
+	assert.Equal(t, "/classic/voina-i-mir.epub", book.Path)
+	assert.Equal(t, "voina-i-mir.epub", book.Name)
+	assert.Equal(t, len(file), book.Bytes)
+	assert.Equal(t, pbc.MimeTypeEPUB, book.MimeType)
+	assert.Equal(t, pbc.FormatEPUB, book.Format)
+	assert.Equal(t, mtime, book.ClientMtime)
+}
+
+func TestClient_Upload_Retry(t *testing.T) {
+	t.Parallel()
+
+	fs, client := newFileServer(t)
+	fs.failures.Store(1)
+
+	file := epubFile()
+
+	_, err := client.Upload(context.Background(), "some.token", "voina-i-mir.epub",
+		bytes.NewReader(file), int64(len(file)), pbc.UploadOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, file, fs.body("/voina-i-mir.epub"))
+}
+
+func TestClient_Upload_NotSeekable(t *testing.T) {
+	t.Parallel()
+
+	fs, client := newFileServer(t)
+	file := []byte("%PDF-1.7 document")
+
+	book, err := client.Upload(context.Background(), "some.token", "document",
+		iotest.OneByteReader(bytes.NewReader(file)), int64(len(file)), pbc.UploadOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, file, fs.body("/document"))
+	assert.Equal(t, pbc.MimeTypePDF, book.MimeType)
+
+	fs.failures.Store(1)
+
+	_, err = client.Upload(context.Background(), "some.token", "document",
+		iotest.OneByteReader(bytes.NewReader(file)), int64(len(file)), pbc.UploadOptions{})
+	require.ErrorIs(t, err, pbc.ErrServer)
+}
+
+func TestSession_Upload_NotSeekable_Reauth(t *testing.T) {
+	t.Parallel()
+
+	fs, client := newFileServer(t)
+	file := []byte("%PDF-1.7 " + strings.Repeat("document", 625))
+
+	var events []pbc.ReauthEvent
+
+	token := pbc.Token{
+		AccessToken:  "some.revoked.token",
+		ExpiresIn:    time.Now().Add(time.Hour),
+		RefreshToken: "some.refresh.token",
+	}
+
+	session := pbc.NewSession(client, pbc.LoginRequest{Provider: "some_provider"}, token,
+		pbc.WithReauth(func(e pbc.ReauthEvent) { events = append(events, e) }),
+	)
+
+	// the rejected attempt drains the reader, so the upload is not repeated with the renewed token
+	_, err := session.Upload(context.Background(), "document",
+		iotest.OneByteReader(bytes.NewReader(file)), int64(len(file)), pbc.UploadOptions{})
+	require.ErrorIs(t, err, pbc.ErrUnauthorized)
+
+	assert.Empty(t, events)
+	assert.Nil(t, fs.body("/document"))
+}